@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/spf13/cobra"
+)
+
+// runClean Primary entry point function for our cache clean command
+func runClean(cmd *cobra.Command) error {
+	cacheDir, err := lib.DefaultGitCacheDir()
+	if err != nil {
+		return err
+	}
+	if err = lib.CleanGitCache(cacheDir); err != nil {
+		return err
+	}
+	lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Removed cached Git repositories from %s\n", cacheDir))
+	return nil
+}
+
+// CleanCmd instantiates the "cache clean" subcommand
+func CleanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "remove all cached Git repositories",
+		Long: `Removes the copies of remote Git repositories kept in the users cache folder.
+Repositories are downloaded again the next time they are used`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runClean(cmd)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to clean cache")
+			}
+			return err
+		},
+	}
+}
+
+// CacheCmd instantiates the "cache" command which groups operations for managing the
+// copies of remote Git repositories kept on disk
+func CacheCmd() *cobra.Command {
+	retval := &cobra.Command{
+		Use:   "cache",
+		Short: "manage cached Git repositories",
+		Long: `Remote Git repositories containing templates and inventories are cached in the
+users cache folder so they only need to be downloaded once. Subsequent runs only fetch
+the changes made since the repositories were last used`,
+	}
+	retval.AddCommand(CleanCmd())
+	return retval
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CacheCleanCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a cache folder containing a cached repository
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CACHE_HOME", tmpDir)
+	t.Setenv("LocalAppData", tmpDir)
+	cacheDir, err := lib.DefaultGitCacheDir()
+	r.NoError(err)
+	r.True(strings.HasPrefix(cacheDir, tmpDir))
+	r.NoError(os.MkdirAll(filepath.Join(cacheDir, "repo"), 0700))
+
+	// When we clean the cache
+	output := new(bytes.Buffer)
+	cmd := CacheCmd()
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetArgs([]string{"clean"})
+	r.NoError(cmd.ExecuteContext(context.TODO()))
+
+	// Then the cached repositories should be removed
+	a.NoDirExists(cacheDir)
+	a.Contains(output.String(), cacheDir)
+}
@@ -2,7 +2,6 @@ package create
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"reflect"
 	"strings"
@@ -13,6 +12,7 @@ import (
 	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -24,51 +24,49 @@ type rootArgs struct {
 	templateName string
 }
 
-// findTemplate looks up a specific template in the template inventory
-// Name names must be of one of the forms:
-// <template_name>
-// <inventory_namespace>.<template_name>
-func findTemplate(appOptions ao.AppOptions, name string) (ao.TemplateOptions, error) {
-	parts := strings.Split(name, ".")
-	if len(parts) > 2 {
-		return ao.TemplateOptions{}, e.AOInvalidTemplateNameError()
-	}
-	var templates []ao.TemplateOptions
-	var newName string
-	if len(parts) == 1 {
-		templates = appOptions.Templates
-		newName = name
-	} else {
-		inv := appOptions.FindInventory(parts[0])
-		if inv == nil {
-			return ao.TemplateOptions{}, e.NewUnknownTemplateError(name)
-		}
-		// TODO: validate remote inventory definition (ie: check for duplication template names, etc.)
+// createFlags parsed command line flags
+type createFlags struct {
+	// setValues 0 or more values for template args, in the form name=value
+	setValues []string
+	// valuesFile optional path to a YAML file containing values for template args
+	valuesFile string
+	// noInput when true the user will not be prompted for template args. Any args
+	// not provided on the command line fall back to their default values
+	noInput bool
+	// dryRun when true the project is generated in memory and the resulting file
+	// tree is displayed, without writing anything to disk
+	dryRun bool
+	// onConflict determines how generated files that conflict with existing files
+	// in the target folder are handled
+	onConflict templateManager.ConflictStrategy
+	// ref optional branch name, tag name or commit hash identifying the revision of a
+	// Git template to use, overriding any ref defined in the template options
+	ref string
+}
 
-		// iterate through all inventory templates
+// loadPresets parses the values for template args provided on the command line
+// Values provided with --set take precedence over those found in the --values file
+func loadPresets(flags createFlags) (map[string]string, error) {
+	retval := map[string]string{}
+	if flags.valuesFile != "" {
 		var err error
-		templates, err = inv.GetTemplateDefinitions()
+		retval, err = templateManager.ParseValuesFile(afero.NewOsFs(), flags.valuesFile)
 		if err != nil {
-			// TODO: keep record of all failed inventory queries and report them
-			//		 as an aggregate
-			// TODO: ignore errors from template definitions if a match for the
-			//		 template can be found elsewhere
-			return ao.TemplateOptions{}, err
+			return nil, err
 		}
-		newName = parts[1]
 	}
-
-	for _, t := range templates {
-		if t.GetName() == newName {
-			return t, nil
+	for _, curValue := range flags.setValues {
+		name, value, found := strings.Cut(curValue, "=")
+		if !found || len(name) == 0 {
+			return nil, e.NewSimpleError("Invalid --set value, expected name=value: " + curValue)
 		}
+		retval[name] = value
 	}
-
-	return ao.TemplateOptions{}, e.NewUnknownTemplateError(name)
+	return retval, nil
 }
 
 // run Primary entry point function for our generator
-func run(cmd *cobra.Command, args rootArgs) error {
+func run(cmd *cobra.Command, args rootArgs, flags createFlags) error {
 	// We have to use cmd.OutOrStdout() to ensure output is redirected to Cobra
 	// stream handler, to facilitate testing (ie: it allows us to capture output
 	// during unit testing to validate results of CLI operations)
@@ -79,28 +77,68 @@ func run(cmd *cobra.Command, args rootArgs) error {
 		return e.NewInternalError("Failed to retrieve app options")
 	}
 
-	curTemplate, err := findTemplate(appOptions, args.templateName)
+	curTemplate, err := shared.FindTemplate(appOptions, args.templateName)
 	if err != nil {
 		return err
 	}
+	if flags.ref != "" {
+		curTemplate.Ref = flags.ref
+		curTemplate.Version = ""
+	}
 	tm, err := templateManager.New(curTemplate)
 	if err != nil {
 		return err
 	}
+	if tm.GetTag() != "" {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Using release %s (%s) matching version %s\n",
+			tm.GetTag(), tm.GetRevision(), curTemplate.Version))
+	}
 
-	if err = tm.GatherParams(cmd); err != nil {
+	presets, err := loadPresets(flags)
+	if err != nil {
+		return err
+	}
+	if err = tm.SetParams(presets); err != nil {
+		return err
+	}
+	if flags.noInput {
+		err = tm.ResolveParams()
+	} else {
+		err = tm.GatherParams(cmd)
+	}
+	if err != nil {
 		return err
 	}
-	lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Generating project %s from template %s...\n", args.targetPath, curTemplate.GetName()))
+	// Generate the project in memory first, so we can show the user what would be
+	// produced, or check for conflicts with existing files, before modifying the local
+	// file system
+	stagingFS := afero.NewMemMapFs()
+	if err = stagingFS.MkdirAll(args.targetPath, 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	if flags.dryRun {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Simulating project %s from template %s...\n", args.targetPath, curTemplate.GetName()))
+	} else {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Generating project %s from template %s...\n", args.targetPath, curTemplate.GetName()))
+	}
+	if err = tm.Generate(stagingFS, args.targetPath); err != nil {
+		return err
+	}
+	// Put an archive file in the root folder summarizing what we did so we can
+	// regenerate or update the project later
+	if err = tm.WriteArchive(stagingFS, args.targetPath); err != nil {
+		return err
+	}
+	if flags.dryRun {
+		return shared.PrintTree(cmd.OutOrStdout(), stagingFS, args.targetPath)
+	}
 
 	// Make sure our output folder exists
 	if err = os.MkdirAll(args.targetPath, 0700); err != nil {
 		return errors.WithStack(err)
 	}
 
-	return tm.Generate(args.targetPath)
-	// TODO: after generating, put an archive file in the root folder summarizing what we did so we
-	//		 can regenerate or update the project later
+	return tm.MergeProject(cmd, stagingFS, afero.NewOsFs(), args.targetPath, flags.onConflict)
 	// TODO: make terminology consistent (ie: config file for the app, manifest file for the template,
 	//		 and something else for storing status of generated project - maybe audit file?
 	//	file in home folder with user options: app options file / user options file
@@ -111,18 +149,8 @@ func run(cmd *cobra.Command, args rootArgs) error {
 
 // validateArgs checks to see if the command line args provided to the app are valid
 func validateArgs(options ao.AppOptions, args []string) error {
-	if lib.DirExists(args[0]) {
-		contents, err := os.ReadDir(args[0])
-		if err != nil {
-			log.Panic(err)
-		}
-		if len(contents) != 0 {
-			return e.NewPathError(args[0], e.PePathNotEmpty)
-		}
-	}
-
 	// Validate template name
-	_, err := findTemplate(options, args[1])
+	_, err := shared.FindTemplate(options, args[1])
 	if err != nil {
 		return err
 	}
@@ -132,10 +160,11 @@ func validateArgs(options ao.AppOptions, args []string) error {
 
 // CreateCmd instantiates the "create" subcommand
 func CreateCmd() *cobra.Command {
-	return &cobra.Command{
+	flags := createFlags{onConflict: templateManager.CsFail}
+	retval := &cobra.Command{
 		Use:   generateUsageLine(),
 		Short: "create a new project from a template",
-		Long:  `Creates a new project in an empty folder using content defined in a template`,
+		Long:  `Creates a new project in a folder using content defined in a template`,
 		Args:  cobra.MinimumNArgs(2),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize our command context from the root command
@@ -150,23 +179,26 @@ func CreateCmd() *cobra.Command {
 				targetPath:   args[0],
 				templateName: args[1],
 			}
-			err := run(cmd, parsedArgs)
+			err := run(cmd, parsedArgs, flags)
 			if err != nil {
-				// https://pkg.go.dev/github.com/pkg/errors#hdr-Retrieving_the_stack_trace_of_an_error_or_wrapper
-				type stackTracer interface {
-					StackTrace() errors.StackTrace
-				}
-				if temp, ok := interface{}(err).(stackTracer); ok {
-					for _, f := range temp.StackTrace() {
-						lib.SNF(fmt.Fprintf(cmd.ErrOrStderr(), "%+s:%d\n", f, f))
-					}
-				}
-				lib.SNF(fmt.Fprintln(cmd.ErrOrStderr(), "Failed to generate project"))
-				lib.SNF(fmt.Fprintln(cmd.ErrOrStderr(), err.Error()))
+				shared.ReportError(cmd, err, "Failed to generate project")
 			}
 			return err
 		},
 	}
+	retval.Flags().StringArrayVar(&flags.setValues, "set", nil,
+		"value for a template arg, in the form name=value (may be repeated)")
+	retval.Flags().StringVar(&flags.valuesFile, "values", "",
+		"path to a YAML file containing values for template args")
+	retval.Flags().BoolVar(&flags.noInput, "no-input", false,
+		"don't prompt for template args, failing if any required args are missing")
+	retval.Flags().BoolVar(&flags.dryRun, "dry-run", false,
+		"display the files that would be generated without writing anything to disk")
+	retval.Flags().Var(&flags.onConflict, "on-conflict",
+		"how to handle generated files that conflict with existing files: fail, skip, overwrite or prompt")
+	retval.Flags().StringVar(&flags.ref, "ref", "",
+		"branch, tag or commit hash of a Git template to generate the project from, overriding any version constraint")
+	return retval
 }
 
 // generateUsageLine dynamically generates a usage line for the app based on the contents
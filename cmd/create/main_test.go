@@ -3,7 +3,6 @@ package create
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"os"
 	"path"
 	"path/filepath"
@@ -13,6 +12,8 @@ import (
 	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
 	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -103,13 +104,9 @@ func Test_ValidateArgsTargetDirNotEmpty(t *testing.T) {
 		}},
 	}
 
-	// when we validate our input args
+	// validation should succeed since conflicts are handled during generation
 	args := []string{destDir, templateName}
-	result := validateArgs(options, args)
-
-	// we expect the proper error to be returned
-	r.Error(result)
-	r.ErrorIs(result, e.NewPathError(destDir, e.PePathNotEmpty))
+	r.NoError(validateArgs(options, args))
 }
 
 func Test_CreateCommandSucceeds(t *testing.T) {
@@ -174,6 +171,434 @@ func Test_CreateCommandSucceeds(t *testing.T) {
 	// TODO: add helper to generate stack trace without duplicate frames
 }
 
+func Test_CreateCommandNoInput(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	outputDir := path.Join(tmpDir, "output")
+
+	// and a values file providing some of the template args
+	valuesFile := path.Join(tmpDir, "answers.yml")
+	r.NoError(os.WriteFile(valuesFile, []byte("project_name: OtherProj\nversion: 1.2.3\n"), 0600))
+
+	// and an app options file with a template pointing to our project
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   templateName,
+		}},
+	}
+
+	// When we trigger the create command without any user input
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	createCmd.SetIn(new(bytes.Buffer))
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{outputDir, templateName, "--no-input", "--values", valuesFile, "--set", "project_name=MyProj"})
+	err = createCmd.ExecuteContext(ctx)
+	r.NoError(err, "CLI command should have succeeded")
+
+	// Then the project should be generated using the values from the command line
+	a.FileExists(filepath.Join(outputDir, "MyProj", "main.txt"))
+	a.NoDirExists(filepath.Join(outputDir, "OtherProj"))
+	a.NotContains(output.String(), "Name of the source code project")
+
+	// and an archive file recording the values should be generated
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), outputDir)
+	r.NoError(err)
+	a.Equal(templateName, archive.Template.Name)
+	a.Equal(map[string]string{"project_name": "MyProj", "version": "1.2.3"}, archive.Args)
+}
+
+func Test_CreateCommandRef(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a Git template with a tagged release followed by newer changes
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "template")
+	manifest := `
+versions:
+  schema: 1.0
+  template: 1.0
+template:
+  args:
+    - name: project_name
+`
+	hash, err := internal.CommitFiles(repoDir, map[string]string{
+		".rejig.yml": manifest,
+		"readme.txt": "Release {{project_name}}",
+	})
+	r.NoError(err)
+	r.NoError(internal.TagCommit(repoDir, "v1.0", hash))
+	_, err = internal.CommitFiles(repoDir, map[string]string{"readme.txt": "Unreleased {{project_name}}"})
+	r.NoError(err)
+
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstGit,
+			Source: "file://" + repoDir,
+			Name:   templateName,
+		}},
+	}
+
+	// When we create a project from the tagged release of the template
+	outputDir := filepath.Join(tmpDir, "output")
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{outputDir, templateName, "--no-input", "--set", "project_name=MyProj", "--ref", "v1.0"})
+	r.NoError(createCmd.ExecuteContext(ctx))
+
+	// Then the project should be generated from the tagged revision
+	contents, err := os.ReadFile(filepath.Join(outputDir, "readme.txt"))
+	r.NoError(err)
+	a.Equal("Release MyProj", string(contents))
+
+	// and the archive should record the ref and the revision it resolved to
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), outputDir)
+	r.NoError(err)
+	a.Equal("v1.0", archive.Template.Ref)
+	a.Equal(hash, archive.Template.Revision)
+}
+
+func Test_CreateCommandVersionConstraint(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a Git template with several tagged releases
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "template")
+	manifest := `
+versions:
+  schema: 1.0
+  template: 1.0
+template:
+  args:
+    - name: project_name
+`
+	hashes := map[string]string{}
+	for _, curRelease := range []string{"v2.0.0", "v2.1.0", "v2.2.0", "v3.0.0"} {
+		hash, err := internal.CommitFiles(repoDir, map[string]string{
+			".rejig.yml": manifest,
+			"readme.txt": curRelease + " {{project_name}}",
+		})
+		r.NoError(err)
+		r.NoError(internal.TagCommit(repoDir, curRelease, hash))
+		hashes[curRelease] = hash
+	}
+
+	// and app options selecting releases using a version constraint
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:    ao.TstGit,
+			Source:  "file://" + repoDir,
+			Name:    templateName,
+			Version: "^2.1",
+		}},
+	}
+
+	// When we create a project from the template
+	outputDir := filepath.Join(tmpDir, "output")
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{outputDir, templateName, "--no-input", "--set", "project_name=MyProj"})
+	r.NoError(createCmd.ExecuteContext(ctx))
+
+	// Then the project should be generated from the highest matching release
+	contents, err := os.ReadFile(filepath.Join(outputDir, "readme.txt"))
+	r.NoError(err)
+	a.Equal("v2.2.0 MyProj", string(contents))
+	a.Contains(output.String(), "Using release v2.2.0 ("+hashes["v2.2.0"]+") matching version ^2.1")
+
+	// and the archive should record the constraint, and the tag and commit it resolved to
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), outputDir)
+	r.NoError(err)
+	a.Equal("^2.1", archive.Template.Constraint)
+	a.Equal("v2.2.0", archive.Template.Tag)
+	a.Equal(hashes["v2.2.0"], archive.Template.Revision)
+}
+
+func Test_CreateCommandVersionNotFound(t *testing.T) {
+	r := require.New(t)
+
+	// Given a Git template with a single release
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "template")
+	hash, err := internal.CommitFiles(repoDir, map[string]string{
+		".rejig.yml": "versions:\n  schema: 1.0\n",
+	})
+	r.NoError(err)
+	r.NoError(internal.TagCommit(repoDir, "v1.0.0", hash))
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:    ao.TstGit,
+			Source:  "file://" + repoDir,
+			Name:    "MyTemplate",
+			Version: "^2.0",
+		}},
+	}
+
+	// When we create a project using a version constraint no release satisfies
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{filepath.Join(tmpDir, "output"), "MyTemplate", "--no-input"})
+	err = createCmd.ExecuteContext(ctx)
+
+	// Then the command should fail
+	r.Error(err)
+	r.Contains(err.Error(), "No release of template MyTemplate matches version ^2.0")
+}
+
+func Test_CreateCommandRefLocalTemplate(t *testing.T) {
+	r := require.New(t)
+
+	// Given a local template
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   "MyTemplate",
+		}},
+	}
+
+	// When we try to create a project from a specific revision of the template
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{filepath.Join(t.TempDir(), "output"), "MyTemplate", "--no-input", "--ref", "v1.0"})
+	err := createCmd.ExecuteContext(ctx)
+
+	// Then the command should fail
+	r.Error(err)
+	r.Contains(err.Error(), "Revisions are not supported by local template MyTemplate")
+}
+
+func Test_CreateCommandNoInputMissingArgs(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	outputDir := path.Join(tmpDir, "output")
+
+	// and an app options file with a template pointing to our project
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   templateName,
+		}},
+	}
+
+	// When we trigger the create command without providing all required args
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	createCmd.SetIn(new(bytes.Buffer))
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{outputDir, templateName, "--no-input"})
+	err = createCmd.ExecuteContext(ctx)
+
+	// The operation should fail, listing every missing arg
+	r.ErrorIs(err, e.NewMissingArgsError([]string{"project_name", "version"}))
+	a.Contains(output.String(), "project_name")
+	a.Contains(output.String(), "version")
+	a.NoDirExists(outputDir)
+}
+
+func Test_CreateCommandDryRun(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	outputDir := path.Join(tmpDir, "output")
+
+	// and an app options file with a template pointing to our project
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   templateName,
+		}},
+	}
+
+	// When we trigger the create command in dry run mode
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	createCmd.SetIn(new(bytes.Buffer))
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{outputDir, templateName, "--dry-run", "--no-input",
+		"--set", "project_name=MyProj", "--set", "version=1.2.3"})
+	err = createCmd.ExecuteContext(ctx)
+	r.NoError(err, "CLI command should have succeeded")
+
+	// Then the generated files should be displayed
+	a.Contains(output.String(), "MyProj/\n")
+	a.Contains(output.String(), "MyProj/main.txt\n")
+	a.Contains(output.String(), "version.txt\n")
+	a.NotContains(output.String(), ".rejig.yml")
+	a.Contains(output.String(), ".rejig.archive.yml\n")
+	a.Regexp(`-rw\S+\s+5 version.txt`, output.String())
+
+	// and nothing should be written to disk
+	a.NoDirExists(outputDir)
+}
+
+func Test_CreateCommandOnConflict(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		strategy string
+		expected string
+	}{
+		"Skip": {
+			strategy: "skip",
+			expected: "existing",
+		},
+		"Overwrite": {
+			strategy: "overwrite",
+			expected: "1.2.3",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given an output folder with an existing file that conflicts with the template
+			tmpDir, err := os.MkdirTemp("", "")
+			r.NoError(err)
+			defer os.RemoveAll(tmpDir)
+			existingFile := path.Join(tmpDir, "version.txt")
+			r.NoError(os.WriteFile(existingFile, []byte("existing"), 0600))
+
+			// and an app options file with a template pointing to our project
+			templateName := "MyTemplate"
+			appOptions := ao.AppOptions{
+				Templates: []ao.TemplateOptions{{
+					Type:   ao.TstLocal,
+					Source: internal.GetProjectDir(),
+					Name:   templateName,
+				}},
+			}
+
+			// When we trigger the create command with a conflict strategy
+			output := new(bytes.Buffer)
+			createCmd := CreateCmd()
+			createCmd.SetOut(output)
+			createCmd.SetErr(output)
+			createCmd.SetIn(new(bytes.Buffer))
+			ctx := context.TODO()
+			ctx = context.WithValue(ctx, shared.CkOptions, appOptions)
+			createCmd.SetArgs([]string{tmpDir, templateName, "--no-input", "--on-conflict", data.strategy,
+				"--set", "project_name=MyProj", "--set", "version=1.2.3"})
+			err = createCmd.ExecuteContext(ctx)
+			r.NoError(err, "CLI command should have succeeded")
+
+			// Then the conflict should be resolved using the selected strategy
+			contents, err := os.ReadFile(existingFile)
+			r.NoError(err)
+			a.Equal(data.expected, string(contents))
+			a.FileExists(filepath.Join(tmpDir, "MyProj", "main.txt"))
+		})
+	}
+}
+
+func Test_CreateCommandConflictFails(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an output folder with an existing file that conflicts with the template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	r.NoError(os.WriteFile(path.Join(tmpDir, "version.txt"), []byte("existing"), 0600))
+
+	// and an app options file with a template pointing to our project
+	templateName := "MyTemplate"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   templateName,
+		}},
+	}
+
+	// When we trigger the create command without a conflict strategy
+	output := new(bytes.Buffer)
+	createCmd := CreateCmd()
+	createCmd.SetOut(output)
+	createCmd.SetErr(output)
+	createCmd.SetIn(new(bytes.Buffer))
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{tmpDir, templateName, "--no-input",
+		"--set", "project_name=MyProj", "--set", "version=1.2.3"})
+	err = createCmd.ExecuteContext(ctx)
+
+	// Then the operation should fail without generating anything
+	r.ErrorIs(err, e.NewFileConflictError([]string{"version.txt"}))
+	a.NoDirExists(filepath.Join(tmpDir, "MyProj"))
+}
+
+func Test_loadPresets(t *testing.T) {
+	r := require.New(t)
+
+	flags := createFlags{
+		setValues: []string{"first=1", "second=a=b", "third="},
+	}
+	result, err := loadPresets(flags)
+	r.NoError(err)
+	r.Equal(map[string]string{"first": "1", "second": "a=b", "third": ""}, result)
+}
+
+func Test_loadPresetsInvalid(t *testing.T) {
+	r := require.New(t)
+
+	flags := createFlags{
+		setValues: []string{"fubar"},
+	}
+	_, err := loadPresets(flags)
+	r.Error(err)
+	r.Contains(err.Error(), "fubar")
+}
+
 func Test_CreateCommandTooFewArgs(t *testing.T) {
 
 	r := require.New(t)
@@ -275,84 +700,3 @@ func Test_CreateCommandGenerateFailure(t *testing.T) {
 	a.Contains(output.String(), "permission denied")
 	a.Contains(output.String(), "main.go")
 }
-
-func Test_FindTemplate(t *testing.T) {
-	r := require.New(t)
-
-	expName := "Fubar"
-	expTempl := ao.TemplateOptions{
-		Name:   expName,
-		Source: "/tmp",
-		Type:   ao.TstLocal,
-	}
-	appOptions := ao.AppOptions{
-		Templates: []ao.TemplateOptions{expTempl},
-	}
-
-	result, err := findTemplate(appOptions, expName)
-	r.NoError(err)
-	r.Equal(expTempl, result)
-}
-
-func Test_FindTemplateInvalidName(t *testing.T) {
-	r := require.New(t)
-
-	expName := "Fubar.Was.Here"
-	appOptions := ao.AppOptions{
-		Templates: []ao.TemplateOptions{},
-	}
-
-	_, err := findTemplate(appOptions, expName)
-	r.ErrorIs(err, e.AOInvalidTemplateNameError())
-}
-
-func Test_FindTemplateFromInventory(t *testing.T) {
-	r := require.New(t)
-
-	// Given a couple of working folders
-	tmpDir, err := os.MkdirTemp("", "")
-	r.NoError(err)
-	defer os.RemoveAll(tmpDir)
-
-	workDirName := path.Join(tmpDir, "mytemplate")
-	err = os.Mkdir(workDirName, 0700)
-	r.NoError(err)
-
-	// And a sample inventory file
-	outputFile := path.Join(tmpDir, ".rejig.inv.yml")
-	expName := "test1"
-	invData := fmt.Sprintf(`
-templates:
-  - name: %s
-    source: %s
-`, expName, workDirName)
-	fh, err := os.Create(outputFile)
-	r.NoError(err)
-	_, err = fh.WriteString(invData)
-	r.NoError(err)
-	r.NoError(fh.Close())
-
-	expTempl := ao.TemplateOptions{
-		Name:   expName,
-		Source: tmpDir,
-		SubDir: path.Join(tmpDir, "mytemplate"),
-		Type:   ao.TstLocal,
-	}
-	expNamespace := "MyNS"
-	tempInvOpts := ao.InventoryOptions{
-		Type:      ao.IstLocal,
-		Source:    tmpDir,
-		Namespace: expNamespace,
-	}
-
-	// TODO: Use dependency injection to add a fake inventory here
-	//		 for searching, so we don't have to construct all the test data
-	appOptions := ao.AppOptions{
-		Templates:   []ao.TemplateOptions{},
-		Inventories: []ao.InventoryOptions{tempInvOpts},
-	}
-
-	result, err := findTemplate(appOptions, expNamespace+"."+expName)
-	r.NoError(err)
-	r.Equal(expTempl, result)
-}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// rootArgs parsed command line arguments
+type rootArgs struct {
+	// projectPath path to the root folder of the project to be compared with its template
+	projectPath string
+}
+
+// diffFlags parsed command line flags
+type diffFlags struct {
+	// json when true the drift report is written to the console in JSON format
+	json bool
+}
+
+// run Primary entry point function for our drift report
+func run(cmd *cobra.Command, args rootArgs, flags diffFlags) error {
+	projectPath, err := filepath.Abs(args.projectPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	projectFS := afero.NewOsFs()
+	archive, err := templateManager.ParseArchive(projectFS, projectPath)
+	if err != nil {
+		return err
+	}
+	tm, err := templateManager.NewFromArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	// Re-render the template in memory using the args the project was generated with
+	templateFS := afero.NewMemMapFs()
+	if err = templateFS.MkdirAll(projectPath, 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = tm.Generate(templateFS, projectPath); err != nil {
+		return err
+	}
+	report, err := templateManager.CompareProject(templateFS, projectFS, projectPath)
+	if err != nil {
+		return err
+	}
+	report.Template = archive.Template.Name
+	report.Revision = tm.GetRevision()
+
+	if flags.json {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		lib.SNF(fmt.Fprintln(cmd.OutOrStdout(), string(data)))
+		return nil
+	}
+	printReport(cmd.OutOrStdout(), report)
+	return nil
+}
+
+// printReport displays a human readable summary of a drift report, followed by the changes
+// made to every modified file
+func printReport(out io.Writer, report templateManager.DriftReport) {
+	if !report.HasDrift() {
+		lib.SNF(fmt.Fprintf(out, "Project matches template %s\n", report.Template))
+		return
+	}
+	for _, curFile := range report.Added {
+		lib.SNF(fmt.Fprintf(out, "added:    %s\n", curFile))
+	}
+	for _, curFile := range report.Removed {
+		lib.SNF(fmt.Fprintf(out, "removed:  %s\n", curFile))
+	}
+	for _, curFile := range report.Modified {
+		lib.SNF(fmt.Fprintf(out, "modified: %s\n", curFile.Path))
+	}
+	for _, curFile := range report.Modified {
+		lib.SNF(fmt.Fprintf(out, "\n%s", curFile.Diff))
+	}
+}
+
+// DiffCmd instantiates the "diff" subcommand
+func DiffCmd() *cobra.Command {
+	flags := diffFlags{}
+	retval := &cobra.Command{
+		Use:   "diff [projectPath]",
+		Short: "report how a project differs from its template",
+		Long: `Re-renders the template a project was generated from, using the template revision
+and args recorded in the project archive, and reports the files which have been added,
+removed or modified in the project since`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedArgs := rootArgs{
+				projectPath: ".",
+			}
+			if len(args) != 0 {
+				parsedArgs.projectPath = args[0]
+			}
+			err := run(cmd, parsedArgs, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to compare project with its template")
+			}
+			return err
+		},
+	}
+	retval.Flags().BoolVar(&flags.json, "json", false,
+		"write the report in JSON format")
+	return retval
+}
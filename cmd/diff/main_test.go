@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// templateFiles files in the template the test projects are generated from
+var templateFiles = map[string]string{
+	".rejig.yml":  internal.SampleManifest,
+	"readme.txt":  "Project {{project_name}}\n",
+	"license.txt": "MIT",
+}
+
+// modifyProject makes local changes to a project generated from templateFiles
+func modifyProject(r *require.Assertions, projectDir string) {
+	r.NoError(os.WriteFile(filepath.Join(projectDir, "readme.txt"), []byte("Project MyProj edited\n"), 0600))
+	r.NoError(os.Remove(filepath.Join(projectDir, "license.txt")))
+	r.NoError(os.WriteFile(filepath.Join(projectDir, "extra.txt"), []byte("extra"), 0600))
+}
+
+// runDiff runs the diff command with the given args
+func runDiff(args ...string) (string, error) {
+	output := new(bytes.Buffer)
+	diffCmd := DiffCmd()
+	diffCmd.SetOut(output)
+	diffCmd.SetErr(output)
+	diffCmd.SetArgs(args)
+	err := diffCmd.ExecuteContext(context.TODO())
+	return output.String(), err
+}
+
+func Test_DiffCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project with local changes
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	_, _, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+	r.NoError(err)
+	modifyProject(r, projectDir)
+
+	// When we compare the project with its template
+	output, err := runDiff(projectDir)
+	r.NoError(err)
+
+	// Then the changes should be reported
+	a.Contains(output, "added:    extra.txt\n")
+	a.Contains(output, "removed:  license.txt\n")
+	a.Contains(output, "modified: readme.txt\n")
+	a.Contains(output, "-Project MyProj\n")
+	a.Contains(output, "+Project MyProj edited\n")
+	a.NotContains(output, templateManager.ArchiveFileName)
+}
+
+func Test_DiffCommandJSON(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project with local changes
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	_, expRevision, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+	r.NoError(err)
+	modifyProject(r, projectDir)
+
+	// When we compare the project with its template in JSON mode
+	output, err := runDiff(projectDir, "--json")
+	r.NoError(err)
+
+	// Then the report should be machine-readable
+	var report templateManager.DriftReport
+	r.NoError(json.Unmarshal([]byte(output), &report))
+	a.Equal("MyTemplate", report.Template)
+	a.Equal(expRevision, report.Revision)
+	a.Equal([]string{"extra.txt"}, report.Added)
+	a.Equal([]string{"license.txt"}, report.Removed)
+	r.Len(report.Modified, 1)
+	a.Equal("readme.txt", report.Modified[0].Path)
+	a.Contains(report.Modified[0].Diff, "+Project MyProj edited\n")
+}
+
+func Test_DiffCommandNoDrift(t *testing.T) {
+	r := require.New(t)
+
+	// Given a project with no local changes
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	_, _, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+	r.NoError(err)
+
+	// When we compare the project with its template
+	output, err := runDiff(projectDir)
+	r.NoError(err)
+
+	// Then no differences should be reported
+	r.Contains(output, "Project matches template MyTemplate")
+}
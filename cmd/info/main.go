@@ -0,0 +1,171 @@
+package info
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/spf13/cobra"
+)
+
+// rootArgs parsed command line arguments
+type rootArgs struct {
+	// templateName name of the template to describe
+	templateName string
+}
+
+// infoFlags parsed command line flags
+type infoFlags struct {
+	// format determines how the template details are displayed
+	format shared.OutputFormat
+}
+
+// templateDetails all data displayed by the info command
+type templateDetails struct {
+	// Name name used to refer to the template from the command line
+	Name string `json:"name" yaml:"name"`
+	// Type protocol used to retrieve the template content
+	Type string `json:"type" yaml:"type"`
+	// Source path or URL where the template is found
+	Source string `json:"source" yaml:"source"`
+	// Revision identifier for the revision of the template that was loaded, if the
+	// template source supports revisions
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	// TemplateInfo summary of the contents of the template
+	templateManager.TemplateInfo `yaml:",inline"`
+}
+
+// run Primary entry point function for our info command
+func run(cmd *cobra.Command, args rootArgs, flags infoFlags) error {
+	appOptions, ok := cmd.Context().Value(shared.CkOptions).(ao.AppOptions)
+	if !ok {
+		return e.NewInternalError("Failed to retrieve app options")
+	}
+	curTemplate, err := shared.FindTemplate(appOptions, args.templateName)
+	if err != nil {
+		return err
+	}
+	tm, err := templateManager.New(curTemplate)
+	if err != nil {
+		return err
+	}
+	summary, err := tm.Describe()
+	if err != nil {
+		return err
+	}
+	details := templateDetails{
+		Name:         args.templateName,
+		Type:         curTemplate.GetType().String(),
+		Source:       curTemplate.GetSource(),
+		Revision:     tm.GetRevision(),
+		TemplateInfo: summary,
+	}
+
+	printed, err := shared.PrintDocument(cmd.OutOrStdout(), flags.format, details)
+	if err != nil || printed {
+		return err
+	}
+	printDetails(cmd.OutOrStdout(), details)
+	return nil
+}
+
+// printDetails displays the details of a template in a human-readable form
+func printDetails(out io.Writer, details templateDetails) {
+	lib.SNF(fmt.Fprintf(out, "Template: %s\n", details.Name))
+	if len(details.Description) != 0 {
+		lib.SNF(fmt.Fprintf(out, "Description: %s\n", details.Description))
+	}
+	if len(details.Tags) != 0 {
+		lib.SNF(fmt.Fprintf(out, "Tags: %s\n", strings.Join(details.Tags, ", ")))
+	}
+	if len(details.Language) != 0 {
+		lib.SNF(fmt.Fprintf(out, "Language: %s\n", details.Language))
+	}
+	if len(details.Maintainer) != 0 {
+		lib.SNF(fmt.Fprintf(out, "Maintainer: %s\n", details.Maintainer))
+	}
+	lib.SNF(fmt.Fprintf(out, "Source: %s (%s)\n", details.Source, details.Type))
+	if len(details.Revision) != 0 {
+		lib.SNF(fmt.Fprintf(out, "Revision: %s\n", details.Revision))
+	}
+
+	lib.SNF(fmt.Fprintln(out, "\nVersions:"))
+	lib.SNF(fmt.Fprintf(out, "  schema: %s\n", details.SchemaVersion))
+	if len(details.AppVersion) != 0 {
+		lib.SNF(fmt.Fprintf(out, "  rejigger: %s\n", details.AppVersion))
+	}
+	if len(details.TemplateVersion) != 0 {
+		lib.SNF(fmt.Fprintf(out, "  template: %s\n", details.TemplateVersion))
+	}
+
+	lib.SNF(fmt.Fprintln(out, "\nArgs:"))
+	if len(details.Args) == 0 {
+		lib.SNF(fmt.Fprintln(out, "  none"))
+	}
+	for _, curArg := range details.Args {
+		lib.SNF(fmt.Fprintf(out, "  %s (%s)", curArg.Name, curArg.Type))
+		if len(curArg.Description) != 0 {
+			lib.SNF(fmt.Fprintf(out, ": %s", curArg.Description))
+		}
+		lib.SNF(fmt.Fprintln(out))
+		if len(curArg.Choices) != 0 {
+			lib.SNF(fmt.Fprintf(out, "    choices: %s\n", strings.Join(curArg.Choices, ", ")))
+		}
+		if len(curArg.Default) != 0 {
+			lib.SNF(fmt.Fprintf(out, "    default: %s\n", curArg.Default))
+		}
+		if len(curArg.When) != 0 {
+			lib.SNF(fmt.Fprintf(out, "    when: %s\n", curArg.When))
+		}
+	}
+
+	if len(details.Features) != 0 {
+		lib.SNF(fmt.Fprintln(out, "\nFeatures:"))
+		for _, curFeature := range details.Features {
+			lib.SNF(fmt.Fprintf(out, "  %s", curFeature.Name))
+			if len(curFeature.Description) != 0 {
+				lib.SNF(fmt.Fprintf(out, ": %s", curFeature.Description))
+			}
+			lib.SNF(fmt.Fprintf(out, "\n    when: %s\n", curFeature.When))
+		}
+	}
+
+	lib.SNF(fmt.Fprintln(out, "\nFiles:"))
+	for _, curFile := range details.Files {
+		if len(curFile.Feature) != 0 {
+			lib.SNF(fmt.Fprintf(out, "  %s [%s]\n", curFile.Path, curFile.Feature))
+		} else {
+			lib.SNF(fmt.Fprintf(out, "  %s\n", curFile.Path))
+		}
+	}
+}
+
+// InfoCmd instantiates the "info" subcommand
+func InfoCmd() *cobra.Command {
+	flags := infoFlags{format: shared.OfTable}
+	retval := &cobra.Command{
+		Use:   "info templateName",
+		Short: "describe a template",
+		Long: `Displays the details of a template, including its versions, the args it
+prompts for, its optional features and the files it may produce`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedArgs := rootArgs{
+				templateName: args[0],
+			}
+			err := run(cmd, parsedArgs, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to describe template")
+			}
+			return err
+		},
+	}
+	retval.Flags().VarP(&flags.format, "output", "o",
+		"output format: table, json or yaml")
+	return retval
+}
@@ -0,0 +1,124 @@
+package info
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleManifest = `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.1
+template:
+  tags: [c, sample]
+  language: c
+  args:
+    - name: project_name
+      description: Name of the source code project
+    - name: license
+      type: enum
+      choices: [MIT, GPL]
+      default: MIT
+    - name: use_docker
+      type: bool
+  features:
+    - name: docker
+      description: Docker support
+      when: use_docker
+      paths: [Dockerfile]
+`
+
+// templateFiles files in the sample template
+var templateFiles = map[string]string{
+	".rejig.yml":              sampleManifest,
+	"Dockerfile":              "FROM scratch",
+	"{{project_name}}/main.c": "",
+}
+
+// sampleTemplate options for the sample template stored in tmpDir
+func sampleTemplate(tmpDir string) ao.TemplateOptions {
+	return ao.TemplateOptions{
+		Type:        ao.TstLocal,
+		Source:      tmpDir,
+		Name:        "MyTemplate",
+		Description: "My sample template",
+	}
+}
+
+func Test_InfoCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a configured template
+	tmpDir := t.TempDir()
+	appOptions, err := internal.NewAppOptions(tmpDir, templateFiles, sampleTemplate(tmpDir))
+	r.NoError(err)
+
+	// When we describe the template
+	output, _, err := internal.RunCommand(InfoCmd(), appOptions, "MyTemplate")
+
+	// Then the details of the template should be displayed
+	r.NoError(err)
+	a.Contains(output, "Template: MyTemplate\n")
+	a.Contains(output, "Description: My sample template\n")
+	a.Contains(output, "Tags: c, sample\n")
+	a.Contains(output, "Language: c\n")
+	a.Contains(output, "Source: "+tmpDir+" (local)\n")
+	a.Contains(output, "  schema: 1.0\n  rejigger: 0.0.1\n  template: 1.1\n")
+	a.Contains(output, "  project_name (string): Name of the source code project\n")
+	a.Contains(output, "  license (enum)\n    choices: MIT, GPL\n    default: MIT\n")
+	a.Contains(output, "  docker: Docker support\n    when: use_docker\n")
+	a.Contains(output, "  Dockerfile [docker]\n")
+	a.Contains(output, "  {{project_name}}/main.c\n")
+	a.NotContains(output, ".rejig.yml")
+}
+
+func Test_InfoCommandJSON(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a configured template
+	tmpDir := t.TempDir()
+	appOptions, err := internal.NewAppOptions(tmpDir, templateFiles, sampleTemplate(tmpDir))
+	r.NoError(err)
+
+	// When we describe the template in JSON format
+	output, _, err := internal.RunCommand(InfoCmd(), appOptions, "MyTemplate", "--output", "json")
+	r.NoError(err)
+
+	// Then the output should contain all of the template details
+	var result templateDetails
+	r.NoError(json.Unmarshal([]byte(output), &result))
+	a.Equal("MyTemplate", result.Name)
+	a.Equal("local", result.Type)
+	a.Equal("1.1", result.TemplateVersion)
+	r.Len(result.Args, 3)
+	a.Equal([]string{"MIT", "GPL"}, result.Args[1].Choices)
+	a.ElementsMatch([]templateManager.TemplateFile{
+		{Path: "Dockerfile", Feature: "docker"},
+		{Path: "{{project_name}}/main.c"},
+	}, result.Files)
+}
+
+func Test_InfoCommandUnknownTemplate(t *testing.T) {
+	r := require.New(t)
+
+	// Given a configured template
+	tmpDir := t.TempDir()
+	appOptions, err := internal.NewAppOptions(tmpDir, templateFiles, sampleTemplate(tmpDir))
+	r.NoError(err)
+
+	// When we describe a template which doesn't exist
+	_, errOutput, err := internal.RunCommand(InfoCmd(), appOptions, "DoesNotExist")
+
+	// Then the command should fail
+	r.Error(err)
+	r.Contains(errOutput, "Failed to describe template")
+}
@@ -1,9 +1,22 @@
 package internal
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"path"
 	"path/filepath"
+	"time"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 // GetProjectDir Gets the path to a specific test project
@@ -23,3 +36,153 @@ func GetProjectDir() string {
 	}
 	return retval
 }
+
+// CommitFiles writes a set of files to a local Git repository, creating the repository if
+// it doesn't already exist, and commits them. Files with no content are removed from the
+// repository. The hash of the new commit is returned
+func CommitFiles(repoDir string, files map[string]string) (string, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		repo, err = git.PlainInit(repoDir, false)
+	}
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	for name, contents := range files {
+		filePath := filepath.Join(repoDir, name)
+		if contents == "" {
+			if _, err = worktree.Remove(name); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			return "", err
+		}
+		if err = os.WriteFile(filePath, []byte(contents), 0600); err != nil {
+			return "", err
+		}
+		if _, err = worktree.Add(name); err != nil {
+			return "", err
+		}
+	}
+	hash, err := worktree.Commit("Update template", &git.CommitOptions{
+		Author: &object.Signature{Name: "Tester", Email: "tester@example.com", When: time.Now()},
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// TagCommit creates a lightweight tag in a local Git repository pointing to the commit
+// with the given hash
+func TagCommit(repoDir string, name string, hash string) error {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	_, err = repo.CreateTag(name, plumbing.NewHash(hash), nil)
+	return err
+}
+
+// SampleManifest manifest for a simple template which only defines a project_name arg
+const SampleManifest = `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      description: Name of the source code project
+`
+
+// NewProject commits a set of template files to a new Git repository in tmpDir, then
+// generates a project from the template named "MyTemplate" with the project_name arg set
+// to "MyProj". Returns the path to the template repository, the hash of the commit the
+// project was generated from and the path to the generated project
+func NewProject(tmpDir string, files map[string]string) (string, string, string, error) {
+	repoDir := filepath.Join(tmpDir, "template")
+	hash, err := CommitFiles(repoDir, files)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	tm, err := templateManager.New(ao.TemplateOptions{
+		Type:   ao.TstGit,
+		Source: "file://" + repoDir,
+		Name:   "MyTemplate",
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+	if err = tm.SetParams(map[string]string{"project_name": "MyProj"}); err != nil {
+		return "", "", "", err
+	}
+	if err = tm.ResolveParams(); err != nil {
+		return "", "", "", err
+	}
+	projectDir := filepath.Join(tmpDir, "project")
+	if err = os.MkdirAll(projectDir, 0700); err != nil {
+		return "", "", "", err
+	}
+	if err = tm.Generate(afero.NewOsFs(), projectDir); err != nil {
+		return "", "", "", err
+	}
+	if err = tm.WriteArchive(afero.NewOsFs(), projectDir); err != nil {
+		return "", "", "", err
+	}
+	return repoDir, hash, projectDir, nil
+}
+
+// WriteFiles writes a set of files beneath rootDir, creating folders as needed
+func WriteFiles(rootDir string, files map[string]string) error {
+	for name, contents := range files {
+		filePath := filepath.Join(rootDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath, []byte(contents), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewAppOptions writes a set of files, like template or inventory definitions, beneath
+// rootDir and creates app options defining the given templates. When the files include
+// an inventory file, a local inventory in rootDir with the namespace "inv" is defined too
+func NewAppOptions(rootDir string, files map[string]string, templates ...ao.TemplateOptions) (ao.AppOptions, error) {
+	if err := WriteFiles(rootDir, files); err != nil {
+		return ao.AppOptions{}, err
+	}
+	retval := ao.AppOptions{Templates: templates}
+	if _, ok := files[ao.InventoryFileName]; ok {
+		retval.Inventories = []ao.InventoryOptions{{
+			Type:      ao.IstLocal,
+			Source:    rootDir,
+			Namespace: "inv",
+		}}
+	}
+	return retval, nil
+}
+
+// RunCommand runs a command with the given app options and args, returning the text it
+// writes to its standard output and error streams
+func RunCommand(cmd *cobra.Command, appOptions ao.AppOptions, args ...string) (string, string, error) {
+	output := new(bytes.Buffer)
+	errOutput := new(bytes.Buffer)
+	// Usage is suppressed by the root command of the app, so errors don't pollute the output
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs(args)
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	err := cmd.ExecuteContext(ctx)
+	return output.String(), errOutput.String(), err
+}
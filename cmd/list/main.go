@@ -0,0 +1,47 @@
+package list
+
+import (
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/spf13/cobra"
+)
+
+// listFlags parsed command line flags
+type listFlags struct {
+	// format determines how the list of templates is displayed
+	format shared.OutputFormat
+}
+
+// run Primary entry point function for our list command
+func run(cmd *cobra.Command, appOptions ao.AppOptions, flags listFlags) error {
+	templates, failures := appOptions.ListTemplates()
+	return shared.PrintTemplates(cmd, flags.format, templates, failures, len(appOptions.Inventories))
+}
+
+// ListCmd instantiates the "list" subcommand
+func ListCmd() *cobra.Command {
+	flags := listFlags{format: shared.OfTable}
+	retval := &cobra.Command{
+		Use:   "list",
+		Short: "list all available templates",
+		Long: `Lists every template defined in the application options, along with every
+template defined in each configured inventory. Inventories which fail to load are
+reported without preventing templates from other inventories from being listed`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appOptions, ok := cmd.Context().Value(shared.CkOptions).(ao.AppOptions)
+			if !ok {
+				return e.CommandContextNotDefined()
+			}
+			err := run(cmd, appOptions, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to list templates")
+			}
+			return err
+		},
+	}
+	retval.Flags().VarP(&flags.format, "output", "o",
+		"output format: table, json or yaml")
+	return retval
+}
@@ -0,0 +1,160 @@
+package list
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const sampleInventory = `
+templates:
+  - name: first
+    source: ./first
+    description: The first template
+  - name: second
+    source: ./second
+`
+
+// remoteTemplate template defined directly in the app options
+var remoteTemplate = ao.TemplateOptions{
+	Type:        ao.TstGit,
+	Source:      "https://github.com/user/repo.git",
+	Name:        "remote",
+	Description: "A remote template",
+}
+
+// addBrokenInventory adds an inventory which does not exist to a set of app options
+func addBrokenInventory(appOptions *ao.AppOptions, tmpDir string) {
+	appOptions.Inventories = append(appOptions.Inventories, ao.InventoryOptions{
+		Type:      ao.IstLocal,
+		Source:    filepath.Join(tmpDir, "missing"),
+		Namespace: "broken",
+	})
+}
+
+func Test_ListCommandTable(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given app options with templates defined directly and in an inventory
+	tmpDir := t.TempDir()
+	appOptions, err := internal.NewAppOptions(tmpDir, map[string]string{ao.InventoryFileName: sampleInventory}, remoteTemplate)
+	r.NoError(err)
+
+	// When we list the templates
+	output, _, err := internal.RunCommand(ListCmd(), appOptions)
+
+	// Then every template should be displayed
+	r.NoError(err)
+	a.Regexp(`NAME\s+TYPE\s+SOURCE\s+DESCRIPTION`, output)
+	a.Regexp(`remote\s+git\s+https://github.com/user/repo.git\s+A remote template`, output)
+	a.Regexp(`inv.first\s+local\s+`+tmpDir+`/first\s+The first template`, output)
+	a.Regexp(`inv.second\s+local\s+`+tmpDir+`/second`, output)
+}
+
+func Test_ListCommandStructured(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tmpDir := t.TempDir()
+	tests := map[string]struct {
+		format    string
+		unmarshal func([]byte, interface{}) error
+	}{
+		"JSON output": {
+			format:    "json",
+			unmarshal: json.Unmarshal,
+		},
+		"YAML output": {
+			format:    "yaml",
+			unmarshal: yaml.Unmarshal,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given app options with an inventory which can't be loaded
+			appOptions, err := internal.NewAppOptions(tmpDir, map[string]string{ao.InventoryFileName: sampleInventory}, remoteTemplate)
+			r.NoError(err)
+			addBrokenInventory(&appOptions, tmpDir)
+
+			// When we list the templates as a structured document
+			output, _, err := internal.RunCommand(ListCmd(), appOptions, "--output", data.format)
+
+			// Then the listing should fail
+			r.Error(err)
+
+			// and the document should describe the available templates and the failed inventory
+			var result shared.TemplateListing
+			r.NoError(data.unmarshal([]byte(output), &result))
+			r.Len(result.Templates, 3)
+			a.Equal(shared.TemplateSummary{
+				Name:        "inv.first",
+				Type:        "local",
+				Source:      tmpDir + "/first",
+				Description: "The first template",
+			}, result.Templates[1])
+			r.Len(result.Errors, 1)
+			a.Equal("broken", result.Errors[0].Namespace)
+			a.NotEmpty(result.Errors[0].Error)
+		})
+	}
+}
+
+func Test_ListCommandInventoryFailure(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given app options with an inventory which can't be loaded
+	tmpDir := t.TempDir()
+	appOptions, err := internal.NewAppOptions(tmpDir, map[string]string{ao.InventoryFileName: sampleInventory}, remoteTemplate)
+	r.NoError(err)
+	addBrokenInventory(&appOptions, tmpDir)
+
+	// When we list the templates
+	output, errOutput, err := internal.RunCommand(ListCmd(), appOptions)
+
+	// Then templates from the other inventories should still be listed
+	r.Error(err)
+	a.Contains(output, "inv.first")
+	a.Contains(output, "remote")
+
+	// and the failed inventory should be reported
+	a.Contains(errOutput, "Failed to load inventory broken")
+	a.Contains(err.Error(), "Failed to load 1 of 2 inventories")
+}
+
+func Test_ListCommandInvalidFormat(t *testing.T) {
+	r := require.New(t)
+
+	// Given empty app options
+	appOptions := ao.AppOptions{}
+
+	// When we request an unsupported output format
+	_, _, err := internal.RunCommand(ListCmd(), appOptions, "--output", "xml")
+
+	// Then the command should fail
+	r.Error(err)
+	r.Contains(err.Error(), "Unsupported output format xml")
+}
+
+func Test_ListCommandNoTemplates(t *testing.T) {
+	r := require.New(t)
+
+	// Given empty app options
+	appOptions := ao.AppOptions{}
+
+	// When we list the templates
+	output, _, err := internal.RunCommand(ListCmd(), appOptions)
+
+	// Then we should be told there are none
+	r.NoError(err)
+	r.Contains(output, "No templates found")
+}
@@ -0,0 +1,120 @@
+package regenerate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// rootArgs parsed command line arguments
+type rootArgs struct {
+	// projectPath path to the root folder of the project to be regenerated
+	projectPath string
+	// targetPath path to the folder where the regenerated project is to be created
+	targetPath string
+}
+
+// regenerateFlags parsed command line flags
+type regenerateFlags struct {
+	// dryRun when true the project is regenerated in memory and the resulting file
+	// tree is displayed, without writing anything to disk
+	dryRun bool
+}
+
+// run Primary entry point function for our regenerator
+func run(cmd *cobra.Command, args rootArgs, flags regenerateFlags) error {
+	projectPath, err := filepath.Abs(args.projectPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), projectPath)
+	if err != nil {
+		return err
+	}
+	if archive.Template.Revision == "" {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(),
+			"Warning: template %s does not support revisions, using its current content\n", archive.Template.Name))
+	}
+
+	lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Loading template %s...\n", archive.Template.Name))
+	tm, err := templateManager.NewFromArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	stagingFS := afero.NewMemMapFs()
+	if err = stagingFS.MkdirAll(args.targetPath, 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	if flags.dryRun {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Simulating project %s from template %s...\n", args.targetPath, archive.Template.Name))
+	} else {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Regenerating project %s from template %s...\n", args.targetPath, archive.Template.Name))
+	}
+	if err = tm.Generate(stagingFS, args.targetPath); err != nil {
+		return err
+	}
+	if err = tm.WriteArchive(stagingFS, args.targetPath); err != nil {
+		return err
+	}
+	if flags.dryRun {
+		return shared.PrintTree(cmd.OutOrStdout(), stagingFS, args.targetPath)
+	}
+
+	if err = os.MkdirAll(args.targetPath, 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	return tm.MergeProject(cmd, stagingFS, afero.NewOsFs(), args.targetPath, templateManager.CsFail)
+}
+
+// validateArgs checks to see if the command line args provided to the app are valid
+func validateArgs(args []string) error {
+	// The regenerated project must not be mixed with any existing content
+	if lib.DirExists(args[1]) {
+		contents, err := os.ReadDir(args[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(contents) != 0 {
+			return e.NewPathError(args[1], e.PePathNotEmpty)
+		}
+	}
+	return nil
+}
+
+// RegenerateCmd instantiates the "regenerate" subcommand
+func RegenerateCmd() *cobra.Command {
+	flags := regenerateFlags{}
+	retval := &cobra.Command{
+		Use:   "regenerate projectPath targetPath",
+		Short: "reproduce a project from the template revision and args it was created with",
+		Long: `Generates a new copy of a project in an empty folder, using the same revision of the
+template and the same template args recorded when the project was originally created`,
+		Args: cobra.ExactArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateArgs(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedArgs := rootArgs{
+				projectPath: args[0],
+				targetPath:  args[1],
+			}
+			err := run(cmd, parsedArgs, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to regenerate project")
+			}
+			return err
+		},
+	}
+	retval.Flags().BoolVar(&flags.dryRun, "dry-run", false,
+		"display the files that would be generated without writing anything to disk")
+	return retval
+}
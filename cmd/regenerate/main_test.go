@@ -0,0 +1,115 @@
+package regenerate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProject generates a project from a Git template, then commits a new revision of the
+// template, returning the commit hash the project was generated from and the path to the
+// generated project
+func newProject(r *require.Assertions, tmpDir string) (string, string) {
+	repoDir, hash, projectDir, err := internal.NewProject(tmpDir, map[string]string{
+		".rejig.yml": internal.SampleManifest,
+		"readme.txt": "Project {{project_name}}",
+	})
+	r.NoError(err)
+
+	_, err = internal.CommitFiles(repoDir, map[string]string{
+		"readme.txt": "Updated project {{project_name}}",
+		"new.txt":    "new",
+	})
+	r.NoError(err)
+	return hash, projectDir
+}
+
+// runRegenerate runs the regenerate command with the given args
+func runRegenerate(args ...string) (string, error) {
+	output := new(bytes.Buffer)
+	regenerateCmd := RegenerateCmd()
+	regenerateCmd.SetOut(output)
+	regenerateCmd.SetErr(output)
+	regenerateCmd.SetArgs(args)
+	err := regenerateCmd.ExecuteContext(context.TODO())
+	return output.String(), err
+}
+
+func Test_RegenerateCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project generated from an older revision of a Git template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	expRevision, projectDir := newProject(r, tmpDir)
+
+	// with local changes made to the project
+	r.NoError(os.WriteFile(filepath.Join(projectDir, "readme.txt"), []byte("edited"), 0600))
+
+	// When we regenerate the project
+	outputDir := filepath.Join(tmpDir, "output")
+	_, err = runRegenerate(projectDir, outputDir)
+	r.NoError(err)
+
+	// Then the project should be reproduced from the original revision of the template
+	contents, err := os.ReadFile(filepath.Join(outputDir, "readme.txt"))
+	r.NoError(err)
+	a.Equal("Project MyProj", string(contents))
+	a.NoFileExists(filepath.Join(outputDir, "new.txt"))
+
+	// and the archive should describe the same template revision and args
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), outputDir)
+	r.NoError(err)
+	a.Equal(expRevision, archive.Template.Revision)
+	a.Equal(map[string]string{"project_name": "MyProj"}, archive.Args)
+}
+
+func Test_RegenerateCommandDryRun(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project generated from a Git template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	_, projectDir := newProject(r, tmpDir)
+
+	// When we regenerate the project in memory
+	outputDir := filepath.Join(tmpDir, "output")
+	output, err := runRegenerate(projectDir, outputDir, "--dry-run")
+	r.NoError(err)
+
+	// Then the regenerated files should be listed
+	a.Regexp(`-rw\S+\s+14 readme.txt`, output)
+	a.Contains(output, templateManager.ArchiveFileName)
+
+	// and nothing should be written to disk
+	a.NoDirExists(outputDir)
+}
+
+func Test_RegenerateCommandTargetNotEmpty(t *testing.T) {
+	r := require.New(t)
+
+	// Given a project generated from a Git template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	_, projectDir := newProject(r, tmpDir)
+
+	// When we try to regenerate the project into a folder with existing content
+	_, err = runRegenerate(projectDir, tmpDir)
+
+	// Then the operation should fail
+	r.ErrorIs(err, e.NewPathError(tmpDir, e.PePathNotEmpty))
+}
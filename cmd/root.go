@@ -4,8 +4,17 @@ import (
 	"context"
 	"os"
 
+	"github.com/TheFriendlyCoder/rejigger/cmd/cache"
 	"github.com/TheFriendlyCoder/rejigger/cmd/create"
+	"github.com/TheFriendlyCoder/rejigger/cmd/diff"
+	"github.com/TheFriendlyCoder/rejigger/cmd/info"
+	"github.com/TheFriendlyCoder/rejigger/cmd/list"
+	"github.com/TheFriendlyCoder/rejigger/cmd/regenerate"
+	"github.com/TheFriendlyCoder/rejigger/cmd/search"
 	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/cmd/template"
+	"github.com/TheFriendlyCoder/rejigger/cmd/update"
+	"github.com/TheFriendlyCoder/rejigger/lib"
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
 	cc "github.com/ivanpirog/coloredcobra"
 	"github.com/pkg/errors"
@@ -44,7 +53,9 @@ specially formatted files stored on disk or in Git repositories`,
 		// By default, cmd will always show the app usage message if the command
 		// fails and returns an error. This flag disables that behavior.
 		SilenceUsage: true,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Adds a --version flag reporting the version embedded in the binary at build time
+		Version: lib.Version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Sanity checks to make sure application is set up properly
 			_, ok := cmd.Context().Value(shared.CkViper).(*viper.Viper)
 			if !ok {
@@ -54,13 +65,36 @@ specially formatted files stored on disk or in Git repositories`,
 			if !ok {
 				panic("Internal configuration error")
 			}
+
+			// Keep copies of remote Git repositories in the users cache folder so
+			// they only need to be downloaded once
+			cacheDir, err := lib.DefaultGitCacheDir()
+			if err != nil {
+				return err
+			}
+			offline, err := cmd.Flags().GetBool("offline")
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			lib.SetGitCache(&lib.GitCache{Dir: cacheDir, Offline: offline})
+			return nil
 		},
 	}
+	retval.PersistentFlags().Bool("offline", false,
+		"use cached copies of remote Git repositories without checking them for changes")
 	// TODO: if we want to pass path to config file on command line, we may be able to use
 	// 		 this helper method to pre-parse the flags from the command line before executing
 	// 		 the actual command, allowing us to load app options before execution
 	// 			retval.ParseFlags()
+	retval.AddCommand(cache.CacheCmd())
 	retval.AddCommand(create.CreateCmd())
+	retval.AddCommand(diff.DiffCmd())
+	retval.AddCommand(info.InfoCmd())
+	retval.AddCommand(list.ListCmd())
+	retval.AddCommand(regenerate.RegenerateCmd())
+	retval.AddCommand(search.SearchCmd())
+	retval.AddCommand(template.TemplateCmd())
+	retval.AddCommand(update.UpdateCmd())
 	return retval
 }
 
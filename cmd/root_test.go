@@ -5,11 +5,14 @@ import (
 	"context"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"testing"
 
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
 	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -72,6 +75,33 @@ func Test_helpCommand(t *testing.T) {
 	a.Contains(actual.String(), "rejigger")
 }
 
+func Test_versionFlag(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Redirect our user home folder to an empty temp dir
+	// to force the command to load default options
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := setHome(t, tmpDir)
+	defer restoreHome(t, oldHome)
+
+	// When we ask for the version of the app
+	rootCmd := RootCmd()
+	actual := new(bytes.Buffer)
+	rootCmd.SetOut(actual)
+	rootCmd.SetErr(actual)
+
+	rootCmd.SetArgs([]string{"--version"})
+	err = Execute(&rootCmd)
+
+	// Then the version embedded in the binary should be displayed
+	r.NoError(err)
+	a.Contains(actual.String(), lib.Version)
+}
+
 func Test_rootSetupValidation(t *testing.T) {
 	// We set up the app environment through Execute now, and the Cobra code should
 	// have some sanity checks in the PreRun to validate that we haven't messed something
@@ -280,3 +310,55 @@ func Test_allColorThemesExist(t *testing.T) {
 		})
 	}
 }
+
+func Test_offlineFlag(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a user config referring to a Git template
+	tmpDir := t.TempDir()
+	oldHome := setHome(t, tmpDir)
+	defer restoreHome(t, oldHome)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmpDir, "cache"))
+	defer lib.SetGitCache(nil)
+
+	repoDir := filepath.Join(tmpDir, "template")
+	_, err := internal.CommitFiles(repoDir, map[string]string{
+		".rejig.yml": "versions:\n  schema: 1.0\n  template: 1.0\ntemplate:\n  args:\n    - name: project_name\n",
+		"readme.txt": "{{project_name}}",
+	})
+	r.NoError(err)
+	config := "templates:\n  - name: MyTemplate\n    type: git\n    source: file://" + repoDir + "\n"
+	r.NoError(os.WriteFile(filepath.Join(tmpDir, ".rejig"), []byte(config), 0600))
+
+	createProject := func(outputDir string, offline bool) error {
+		args := []string{"create", outputDir, "MyTemplate", "--no-input", "--set", "project_name=MyProj"}
+		if offline {
+			args = append(args, "--offline")
+		}
+		rootCmd := RootCmd()
+		actual := new(bytes.Buffer)
+		rootCmd.SetOut(actual)
+		rootCmd.SetErr(actual)
+		rootCmd.SetArgs(args)
+		return Execute(&rootCmd)
+	}
+
+	// When we create a project while offline before the template has been cached
+	err = createProject(filepath.Join(tmpDir, "first"), true)
+
+	// Then the operation should fail
+	r.Error(err)
+
+	// When we create a project while online, and then again while offline once the
+	// template repository is no longer available
+	r.NoError(createProject(filepath.Join(tmpDir, "second"), false))
+	r.NoError(os.RemoveAll(repoDir))
+	err = createProject(filepath.Join(tmpDir, "third"), true)
+
+	// Then the cached copy of the template should be used
+	r.NoError(err)
+	contents, err := os.ReadFile(filepath.Join(tmpDir, "third", "readme.txt"))
+	r.NoError(err)
+	a.Equal("MyProj", string(contents))
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/spf13/cobra"
+)
+
+// rootArgs parsed command line arguments
+type rootArgs struct {
+	// query text to search for
+	query string
+}
+
+// searchFlags parsed command line flags
+type searchFlags struct {
+	// format determines how the matching templates are displayed
+	format shared.OutputFormat
+}
+
+// run Primary entry point function for our search command
+func run(cmd *cobra.Command, appOptions ao.AppOptions, args rootArgs, flags searchFlags) error {
+	templates, failures := appOptions.ListTemplates()
+	var matches []ao.TemplateEntry
+	for _, curEntry := range templates {
+		if curEntry.Matches(args.query) {
+			matches = append(matches, curEntry)
+		}
+	}
+	return shared.PrintTemplates(cmd, flags.format, matches, failures, len(appOptions.Inventories))
+}
+
+// SearchCmd instantiates the "search" subcommand
+func SearchCmd() *cobra.Command {
+	flags := searchFlags{format: shared.OfTable}
+	retval := &cobra.Command{
+		Use:   "search query",
+		Short: "search for templates",
+		Long: `Lists the templates, from the application options and every configured inventory,
+whose name, description or tags contain the given text, ignoring case`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appOptions, ok := cmd.Context().Value(shared.CkOptions).(ao.AppOptions)
+			if !ok {
+				return e.CommandContextNotDefined()
+			}
+			parsedArgs := rootArgs{
+				query: args[0],
+			}
+			err := run(cmd, appOptions, parsedArgs, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to search templates")
+			}
+			return err
+		},
+	}
+	retval.Flags().VarP(&flags.format, "output", "o",
+		"output format: table, json or yaml")
+	return retval
+}
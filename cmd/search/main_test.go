@@ -0,0 +1,99 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleInventory = `
+templates:
+  - name: goService
+    source: ./goService
+    description: Microservice with a REST API
+    tags: [backend, docker]
+    language: go
+  - name: pyLib
+    source: ./pyLib
+    description: Python library
+    tags: [library]
+    language: python
+`
+
+// webAppTemplate template defined directly in the app options
+var webAppTemplate = ao.TemplateOptions{
+	Type:        ao.TstLocal,
+	Source:      "/path/to/template",
+	Name:        "webApp",
+	Description: "Front end for a REST service",
+}
+
+func Test_SearchCommand(t *testing.T) {
+	r := require.New(t)
+
+	tmpDir := t.TempDir()
+	tests := map[string]struct {
+		query    string
+		expected []string
+	}{
+		"Match description across sources": {
+			query:    "rest",
+			expected: []string{"webApp", "inv.goService"},
+		},
+		"Match tag": {
+			query:    "LIBRARY",
+			expected: []string{"inv.pyLib"},
+		},
+		"Match namespace": {
+			query:    "inv.",
+			expected: []string{"inv.goService", "inv.pyLib"},
+		},
+		"No matches": {
+			query:    "rust",
+			expected: []string{},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given templates defined in the app options and in an inventory
+			appOptions, err := internal.NewAppOptions(tmpDir, map[string]string{ao.InventoryFileName: sampleInventory}, webAppTemplate)
+			r.NoError(err)
+
+			// When we search for templates
+			output, _, err := internal.RunCommand(SearchCmd(), appOptions, data.query, "--output", "json")
+			r.NoError(err)
+
+			// Then only the matching templates should be listed
+			var result shared.TemplateListing
+			r.NoError(json.Unmarshal([]byte(output), &result))
+			names := []string{}
+			for _, curTemplate := range result.Templates {
+				names = append(names, curTemplate.Name)
+			}
+			assert.Equal(t, data.expected, names)
+		})
+	}
+}
+
+func Test_SearchCommandTable(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given templates defined in an inventory
+	appOptions, err := internal.NewAppOptions(t.TempDir(), map[string]string{ao.InventoryFileName: sampleInventory}, webAppTemplate)
+	r.NoError(err)
+
+	// When we search for templates
+	output, _, err := internal.RunCommand(SearchCmd(), appOptions, "docker")
+
+	// Then the matching templates should be displayed along with their tags
+	r.NoError(err)
+	a.Regexp(`inv.goService\s+local\s+.*Microservice with a REST API\s+backend, docker`, output)
+	a.NotContains(output, "pyLib")
+}
@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"fmt"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ReportError displays the details of an error produced by a command, including the stack
+// trace of the error when one is available, followed by a brief summary of the operation
+// that failed
+func ReportError(cmd *cobra.Command, err error, summary string) {
+	// https://pkg.go.dev/github.com/pkg/errors#hdr-Retrieving_the_stack_trace_of_an_error_or_wrapper
+	type stackTracer interface {
+		StackTrace() errors.StackTrace
+	}
+	if temp, ok := interface{}(err).(stackTracer); ok {
+		for _, f := range temp.StackTrace() {
+			lib.SNF(fmt.Fprintf(cmd.ErrOrStderr(), "%+s:%d\n", f, f))
+		}
+	}
+	lib.SNF(fmt.Fprintln(cmd.ErrOrStderr(), summary))
+	lib.SNF(fmt.Fprintln(cmd.ErrOrStderr(), err.Error()))
+}
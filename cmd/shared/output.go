@@ -0,0 +1,114 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat enum for all supported formats for displaying command results
+type OutputFormat int64
+
+const (
+	// OfUndefined No format defined. Treated the same as OfTable.
+	OfUndefined OutputFormat = iota
+	// OfUnknown Format provided but not currently supported
+	OfUnknown
+	// OfTable Results are displayed as human-readable text
+	OfTable
+	// OfJSON Results are displayed as a JSON document
+	OfJSON
+	// OfYAML Results are displayed as a YAML document
+	OfYAML
+)
+
+// toString Converts the value from our enumeration to a string representation
+func (o *OutputFormat) toString() string {
+	switch *o {
+	case OfTable:
+		return "table"
+	case OfJSON:
+		return "json"
+	case OfYAML:
+		return "yaml"
+	case OfUndefined:
+		fallthrough
+	case OfUnknown:
+		fallthrough
+	default:
+		return ""
+	}
+}
+
+// fromString populates our enumeration from an arbitrary character string
+func (o *OutputFormat) fromString(value string) {
+	switch value {
+	case "table":
+		*o = OfTable
+	case "json":
+		*o = OfJSON
+	case "yaml":
+		*o = OfYAML
+	case "":
+		*o = OfUndefined
+	default:
+		*o = OfUnknown
+	}
+}
+
+// String Converts the value from our enumeration to a string representation, for
+// use when displaying command line flags
+func (o *OutputFormat) String() string {
+	return o.toString()
+}
+
+// Set populates our enumeration from the value of a command line flag
+func (o *OutputFormat) Set(value string) error {
+	var temp OutputFormat
+	temp.fromString(value)
+	if temp == OfUnknown || temp == OfUndefined {
+		return e.NewSimpleError("Unsupported output format " + value + ", expected one of table, json, yaml")
+	}
+	*o = temp
+	return nil
+}
+
+// Type describes the data type of our enumeration when displaying command line flags
+func (o *OutputFormat) Type() string {
+	return "format"
+}
+
+// PrintDocument writes data to the console as a JSON or YAML document, depending on the
+// output format. Returns false if the output format is not a structured document format,
+// in which case the caller is expected to display the data itself
+func PrintDocument(out io.Writer, format OutputFormat, data interface{}) (bool, error) {
+	switch format {
+	case OfJSON:
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return true, errors.WithStack(err)
+		}
+		lib.SNF(fmt.Fprintln(out, string(encoded)))
+		return true, nil
+	case OfYAML:
+		encoded, err := yaml.Marshal(data)
+		if err != nil {
+			return true, errors.WithStack(err)
+		}
+		lib.SNF(fmt.Fprint(out, string(encoded)))
+		return true, nil
+	case OfTable:
+		fallthrough
+	case OfUndefined:
+		fallthrough
+	case OfUnknown:
+		fallthrough
+	default:
+		return false, nil
+	}
+}
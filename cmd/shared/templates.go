@@ -0,0 +1,154 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/spf13/cobra"
+)
+
+// FindTemplate looks up a specific template in the template inventory
+// Name names must be of one of the forms:
+// <template_name>
+// <inventory_namespace>.<template_name>
+func FindTemplate(appOptions ao.AppOptions, name string) (ao.TemplateOptions, error) {
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return ao.TemplateOptions{}, e.AOInvalidTemplateNameError()
+	}
+	var templates []ao.TemplateOptions
+	var newName string
+	if len(parts) == 1 {
+		templates = appOptions.Templates
+		newName = name
+	} else {
+		inv := appOptions.FindInventory(parts[0])
+		if inv == nil {
+			return ao.TemplateOptions{}, e.NewUnknownTemplateError(name)
+		}
+		// TODO: validate remote inventory definition (ie: check for duplication template names, etc.)
+
+		// iterate through all inventory templates
+		var err error
+		templates, err = inv.GetTemplateDefinitions()
+		if err != nil {
+			// TODO: keep record of all failed inventory queries and report them
+			//		 as an aggregate
+			// TODO: ignore errors from template definitions if a match for the
+			//		 template can be found elsewhere
+			return ao.TemplateOptions{}, err
+		}
+		newName = parts[1]
+	}
+
+	for _, t := range templates {
+		if t.GetName() == newName {
+			return t, nil
+		}
+	}
+
+	return ao.TemplateOptions{}, e.NewUnknownTemplateError(name)
+}
+
+// TemplateSummary summary of a template displayed when listing templates
+type TemplateSummary struct {
+	// Name fully qualified name used to refer to the template from the command line
+	Name string `json:"name" yaml:"name"`
+	// Type protocol used to retrieve the template content
+	Type string `json:"type" yaml:"type"`
+	// Source path or URL where the template is found
+	Source string `json:"source" yaml:"source"`
+	// Description descriptive text explaining the purpose of the template
+	Description string `json:"description" yaml:"description"`
+	// Tags keywords describing the template
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Maintainer person or team who maintains the template
+	Maintainer string `json:"maintainer,omitempty" yaml:"maintainer,omitempty"`
+	// Language programming language used by projects generated from the template
+	Language string `json:"language,omitempty" yaml:"language,omitempty"`
+}
+
+// InventoryProblem describes an inventory which could not be loaded
+type InventoryProblem struct {
+	// Namespace namespace of the inventory that failed to load
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Error description of the failure
+	Error string `json:"error" yaml:"error"`
+}
+
+// TemplateListing all data displayed when listing templates
+type TemplateListing struct {
+	// Templates every template being listed
+	Templates []TemplateSummary `json:"templates" yaml:"templates"`
+	// Errors problems encountered loading inventories
+	Errors []InventoryProblem `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// getSource gets the location of a template, including the sub folder it is found in
+func getSource(template ao.TemplateOptions) string {
+	if len(template.SubDir) == 0 {
+		return template.GetSource()
+	}
+	return strings.TrimSuffix(template.GetSource(), "/") + "/" + strings.TrimPrefix(template.SubDir, "./")
+}
+
+// PrintTemplates displays a list of templates, along with any inventories which failed
+// to load, in the given output format. Returns an error if any inventories failed to load,
+// once all the templates which could be found have been displayed
+func PrintTemplates(cmd *cobra.Command, format OutputFormat, templates []ao.TemplateEntry, failures []ao.InventoryFailure, inventoryCount int) error {
+	results := TemplateListing{Templates: make([]TemplateSummary, 0, len(templates))}
+	for _, curEntry := range templates {
+		results.Templates = append(results.Templates, TemplateSummary{
+			Name:        curEntry.QualifiedName,
+			Type:        curEntry.Template.GetType().String(),
+			Source:      getSource(curEntry.Template),
+			Description: curEntry.Template.Description,
+			Tags:        curEntry.Template.Tags,
+			Maintainer:  curEntry.Template.Maintainer,
+			Language:    curEntry.Template.Language,
+		})
+	}
+	for _, curFailure := range failures {
+		results.Errors = append(results.Errors, InventoryProblem{
+			Namespace: curFailure.Namespace,
+			Error:     curFailure.Err.Error(),
+		})
+	}
+
+	printed, err := PrintDocument(cmd.OutOrStdout(), format, results)
+	if err != nil {
+		return err
+	}
+	if !printed {
+		printTemplateTable(cmd.OutOrStdout(), results.Templates)
+		for _, curProblem := range results.Errors {
+			lib.SNF(fmt.Fprintf(cmd.ErrOrStderr(), "Failed to load inventory %s: %s\n",
+				curProblem.Namespace, curProblem.Error))
+		}
+	}
+	if len(failures) != 0 {
+		return e.NewSimpleError(fmt.Sprintf("Failed to load %d of %d inventories",
+			len(failures), inventoryCount))
+	}
+	return nil
+}
+
+// printTemplateTable displays a list of templates as a human-readable table
+func printTemplateTable(out io.Writer, templates []TemplateSummary) {
+	if len(templates) == 0 {
+		lib.SNF(fmt.Fprintln(out, "No templates found"))
+		return
+	}
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	lib.SNF(fmt.Fprintln(writer, "NAME\tTYPE\tSOURCE\tDESCRIPTION\tTAGS"))
+	for _, curTemplate := range templates {
+		lib.SNF(fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", curTemplate.Name, curTemplate.Type,
+			curTemplate.Source, curTemplate.Description, strings.Join(curTemplate.Tags, ", ")))
+	}
+	lib.SNF(writer.Flush())
+}
@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindTemplate(t *testing.T) {
+	r := require.New(t)
+
+	expName := "Fubar"
+	expTempl := ao.TemplateOptions{
+		Name:   expName,
+		Source: "/tmp",
+		Type:   ao.TstLocal,
+	}
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{expTempl},
+	}
+
+	result, err := FindTemplate(appOptions, expName)
+	r.NoError(err)
+	r.Equal(expTempl, result)
+}
+
+func Test_FindTemplateInvalidName(t *testing.T) {
+	r := require.New(t)
+
+	expName := "Fubar.Was.Here"
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{},
+	}
+
+	_, err := FindTemplate(appOptions, expName)
+	r.ErrorIs(err, e.AOInvalidTemplateNameError())
+}
+
+func Test_FindTemplateFromInventory(t *testing.T) {
+	r := require.New(t)
+
+	// Given a couple of working folders
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	workDirName := path.Join(tmpDir, "mytemplate")
+	err = os.Mkdir(workDirName, 0700)
+	r.NoError(err)
+
+	// And a sample inventory file
+	outputFile := path.Join(tmpDir, ".rejig.inv.yml")
+	expName := "test1"
+	invData := fmt.Sprintf(`
+templates:
+  - name: %s
+    source: %s
+`, expName, workDirName)
+	fh, err := os.Create(outputFile)
+	r.NoError(err)
+	_, err = fh.WriteString(invData)
+	r.NoError(err)
+	r.NoError(fh.Close())
+
+	expTempl := ao.TemplateOptions{
+		Name:   expName,
+		Source: tmpDir,
+		SubDir: path.Join(tmpDir, "mytemplate"),
+		Type:   ao.TstLocal,
+	}
+	expNamespace := "MyNS"
+	tempInvOpts := ao.InventoryOptions{
+		Type:      ao.IstLocal,
+		Source:    tmpDir,
+		Namespace: expNamespace,
+	}
+
+	// TODO: Use dependency injection to add a fake inventory here
+	//		 for searching, so we don't have to construct all the test data
+	appOptions := ao.AppOptions{
+		Templates:   []ao.TemplateOptions{},
+		Inventories: []ao.InventoryOptions{tempInvOpts},
+	}
+
+	result, err := FindTemplate(appOptions, expNamespace+"."+expName)
+	r.NoError(err)
+	r.Equal(expTempl, result)
+}
@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// PrintTree displays the mode, size and path of every file and folder found beneath
+// the given root folder
+func PrintTree(out io.Writer, srcFS afero.Fs, rootDir string) error {
+	err := afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if relPath == "." {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		if info.IsDir() {
+			lib.SNF(fmt.Fprintf(out, "%s %10s %s/\n", info.Mode(), "-", relPath))
+		} else {
+			lib.SNF(fmt.Fprintf(out, "%s %10d %s\n", info.Mode(), info.Size(), relPath))
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list generated files")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package template
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// lintArgs parsed command line arguments for the lint subcommand
+type lintArgs struct {
+	// templatePath path to the root folder of the template to be checked
+	templatePath string
+}
+
+// runLint Primary entry point function for our template lint command
+func runLint(cmd *cobra.Command, args lintArgs) error {
+	templatePath, err := filepath.Abs(args.templatePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	srcFS := afero.NewOsFs()
+
+	// Folders containing an inventory file are checked as inventories
+	inventoryFile := filepath.Join(templatePath, ao.InventoryFileName)
+	isInventory, err := afero.Exists(srcFS, inventoryFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if isInventory {
+		if err = ao.ValidateInventory(srcFS, inventoryFile); err != nil {
+			return err
+		}
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "No problems found in inventory %s\n", templatePath))
+		return nil
+	}
+
+	problems, err := templateManager.LintTemplate(srcFS, templatePath)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(problems) == 0 {
+		lib.SNF(fmt.Fprintf(out, "No problems found in template %s\n", templatePath))
+		return nil
+	}
+	for _, curProblem := range problems {
+		lib.SNF(fmt.Fprintln(out, curProblem))
+	}
+	return e.NewSimpleError(fmt.Sprintf("Found %d problems in template %s", len(problems), templatePath))
+}
+
+// LintCmd instantiates the "template lint" subcommand
+func LintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [templatePath]",
+		Short: "check a template or inventory for problems",
+		Long: `Checks the manifest of a template for unknown keys, missing required keys and
+invalid values, compiles every templated file and file name to check for syntax errors,
+and reports variables which are used by the template without being declared as args,
+as well as args which are never used.
+
+Folders containing an inventory file are checked as inventories instead, reporting
+unknown keys, missing required keys and invalid values in the inventory file`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedArgs := lintArgs{
+				templatePath: ".",
+			}
+			if len(args) != 0 {
+				parsedArgs.templatePath = args[0]
+			}
+			err := runLint(cmd, parsedArgs)
+			if err != nil {
+				shared.ReportError(cmd, err, "Template lint failed")
+			}
+			return err
+		},
+	}
+}
+
+// TemplateCmd instantiates the "template" command which groups operations used by
+// template authors
+func TemplateCmd() *cobra.Command {
+	retval := &cobra.Command{
+		Use:   "template",
+		Short: "tools for template authors",
+		Long:  `Operations used to develop and maintain rejigger templates`,
+	}
+	retval.AddCommand(LintCmd())
+	return retval
+}
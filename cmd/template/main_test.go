@@ -0,0 +1,129 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleManifest = `
+versions:
+  schema: 1.0
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      description: Name of the source code project
+`
+
+// writeTemplate creates a template in the given folder with the given files
+func writeTemplate(r *require.Assertions, rootDir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(rootDir, filepath.FromSlash(name))
+		r.NoError(os.MkdirAll(filepath.Dir(path), 0700))
+		r.NoError(os.WriteFile(path, []byte(contents), 0600))
+	}
+}
+
+// runLintCmd runs the template lint command with the given args
+func runLintCmd(args ...string) (string, error) {
+	output := new(bytes.Buffer)
+	cmd := TemplateCmd()
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetArgs(append([]string{"lint"}, args...))
+	err := cmd.ExecuteContext(context.TODO())
+	return output.String(), err
+}
+
+func Test_LintCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template without any problems
+	tmpDir := t.TempDir()
+	writeTemplate(r, tmpDir, map[string]string{
+		".rejig.yml": sampleManifest,
+		"readme.txt": "Project {{ project_name }}\n",
+	})
+
+	// When we lint the template
+	output, err := runLintCmd(tmpDir)
+
+	// Then no problems should be reported
+	r.NoError(err)
+	a.Contains(output, "No problems found")
+}
+
+func Test_LintCommandProblems(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template which uses an undeclared variable
+	tmpDir := t.TempDir()
+	writeTemplate(r, tmpDir, map[string]string{
+		".rejig.yml": sampleManifest,
+		"readme.txt": "Project {{ project_name }}\n\nBy {{ author }}\n",
+	})
+
+	// When we lint the template
+	output, err := runLintCmd(tmpDir)
+
+	// Then the problem should be reported along with its location
+	r.Error(err)
+	a.Contains(output, "readme.txt:3: variable author is not declared as a template arg")
+	a.Contains(output, "Found 1 problems")
+}
+
+func Test_LintCommandInvalidManifest(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with an unknown key in its manifest
+	tmpDir := t.TempDir()
+	writeTemplate(r, tmpDir, map[string]string{
+		".rejig.yml": sampleManifest + "    - nmae: typo\n",
+		"readme.txt": "Project {{ project_name }}\n",
+	})
+
+	// When we lint the template
+	output, err := runLintCmd(tmpDir)
+
+	// Then the problem should be reported along with its location in the manifest
+	r.Error(err)
+	a.Contains(output, "unknown key template.args[1].nmae")
+}
+
+func Test_LintCommandInventory(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an inventory with a typo and a duplicate template name
+	tmpDir := t.TempDir()
+	writeTemplate(r, tmpDir, map[string]string{
+		".rejig.inv.yml": "templates:\n  - name: first\n    source: first\n    sub_dir: docs\n  - name: first\n    source: second\n",
+	})
+
+	// When we lint the inventory
+	output, err := runLintCmd(tmpDir)
+
+	// Then every problem should be reported in a single error
+	r.Error(err)
+	a.Contains(output, "unknown key templates[0].sub_dir")
+	a.Contains(output, "there are 2 templates with the name first")
+
+	// When we fix the problems and lint the inventory again
+	writeTemplate(r, tmpDir, map[string]string{
+		".rejig.inv.yml": "templates:\n  - name: first\n    source: first\n  - name: second\n    source: second\n",
+	})
+	output, err = runLintCmd(tmpDir)
+
+	// Then no problems should be reported
+	r.NoError(err)
+	a.Contains(output, "No problems found in inventory")
+}
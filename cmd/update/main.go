@@ -0,0 +1,187 @@
+package update
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// rootArgs parsed command line arguments
+type rootArgs struct {
+	// projectPath path to the root folder of the project to be updated
+	projectPath string
+}
+
+// updateFlags parsed command line flags
+type updateFlags struct {
+	// noInput when true the user will not be prompted for values for template args
+	// added since the project was generated. Such args fall back to their default values
+	noInput bool
+	// reject when true, template changes which can not be merged into a project file
+	// are saved to a .rej file alongside it, rather than adding conflict markers to it
+	reject bool
+	// runHooks when true, commands defined by template migrations are run without
+	// asking the user to confirm them first
+	runHooks bool
+	// noHooks when true, commands defined by template migrations are not run
+	noHooks bool
+}
+
+// run Primary entry point function for our updater
+func run(cmd *cobra.Command, args rootArgs, flags updateFlags) error {
+	projectPath, err := filepath.Abs(args.projectPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	projectFS := afero.NewOsFs()
+	archive, err := templateManager.ParseArchive(projectFS, projectPath)
+	if err != nil {
+		return err
+	}
+	if archive.Template.Revision == "" {
+		return e.NewSimpleError(fmt.Sprintf(
+			"Template %s does not support revisions, unable to update project %s", archive.Template.Name, projectPath))
+	}
+
+	lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Loading template %s...\n", archive.Template.Name))
+	oldTemplate, err := templateManager.NewFromArchive(archive)
+	if err != nil {
+		return err
+	}
+	newTemplate, err := templateManager.New(archive.GetTemplateOptions())
+	if err != nil {
+		return err
+	}
+	if newTemplate.GetRevision() == archive.Template.Revision {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Project %s is already up to date\n", projectPath))
+		return nil
+	}
+
+	migrations, err := newTemplate.GetMigrations(archive.Template.Version)
+	if err != nil {
+		return err
+	}
+
+	// Reuse the values from the original project, and gather values for any new args
+	// not provided by the template migrations
+	if err = newTemplate.SetArchivedParams(archive.Args); err != nil {
+		return err
+	}
+	if err = newTemplate.SetMigrationParams(migrations); err != nil {
+		return err
+	}
+	if flags.noInput {
+		err = newTemplate.ResolveParams()
+	} else {
+		err = newTemplate.GatherParams(cmd)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Generate both revisions of the template in memory so we can merge the differences
+	// between them into the project
+	baseFS := afero.NewMemMapFs()
+	if err = oldTemplate.Generate(baseFS, projectPath); err != nil {
+		return err
+	}
+	newFS := afero.NewMemMapFs()
+	if err = newTemplate.Generate(newFS, projectPath); err != nil {
+		return err
+	}
+
+	lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Updating project %s to revision %s...\n", projectPath, newTemplate.GetRevision()))
+	err = templateManager.ApplyMigrations(cmd.OutOrStdout(), migrations, baseFS, projectFS, projectPath)
+	if err != nil {
+		return err
+	}
+	conflicts, err := templateManager.UpdateProject(cmd.OutOrStdout(), baseFS, newFS, projectFS, projectPath, flags.reject)
+	if err != nil {
+		return err
+	}
+	// The archive is updated even when there are conflicts, since the changes have been
+	// applied to the project and only need to be resolved by the user
+	if err = newTemplate.WriteArchive(projectFS, projectPath); err != nil {
+		return err
+	}
+	hooks, err := newTemplate.RenderMigrationHooks(migrations)
+	if err != nil {
+		return err
+	}
+	if err = runHooks(cmd, hooks, newTemplate.ConfirmMigrationHooks, projectPath, flags); err != nil {
+		return err
+	}
+	if len(conflicts) != 0 {
+		return e.NewMergeConflictError(conflicts)
+	}
+	return nil
+}
+
+// runHooks runs the shell commands defined by template migrations from the project folder.
+// Templates may come from anywhere, so the commands are always displayed first and are
+// only run when the user confirms them or explicitly asks for them to be run
+func runHooks(cmd *cobra.Command, commands []string, confirm func(*cobra.Command) (bool, error), projectPath string, flags updateFlags) error {
+	if flags.noHooks || len(commands) == 0 {
+		return nil
+	}
+	out := cmd.OutOrStdout()
+	lib.SNF(fmt.Fprintln(out, "The template migrations define the following commands:"))
+	for _, curCommand := range commands {
+		lib.SNF(fmt.Fprintf(out, "\t%s\n", curCommand))
+	}
+
+	confirmed := flags.runHooks
+	if !confirmed && !flags.noInput {
+		var err error
+		if confirmed, err = confirm(cmd); err != nil {
+			return err
+		}
+	}
+	if !confirmed {
+		lib.SNF(fmt.Fprintln(out, "Skipped migration commands, pass --run-hooks to run them"))
+		return nil
+	}
+	return templateManager.RunMigrationHooks(out, commands, projectPath)
+}
+
+// UpdateCmd instantiates the "update" subcommand
+func UpdateCmd() *cobra.Command {
+	flags := updateFlags{}
+	retval := &cobra.Command{
+		Use:   "update [projectPath]",
+		Short: "update a project to the latest version of its template",
+		Long: `Applies the changes made to a template since a project was generated from it to
+the project, preserving any changes made to the project in the meantime`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedArgs := rootArgs{
+				projectPath: ".",
+			}
+			if len(args) != 0 {
+				parsedArgs.projectPath = args[0]
+			}
+			err := run(cmd, parsedArgs, flags)
+			if err != nil {
+				shared.ReportError(cmd, err, "Failed to update project")
+			}
+			return err
+		},
+	}
+	retval.Flags().BoolVar(&flags.noInput, "no-input", false,
+		"don't prompt for new template args, failing if any required args are missing")
+	retval.Flags().BoolVar(&flags.reject, "reject", false,
+		"save changes that can't be merged to .rej files instead of adding conflict markers")
+	retval.Flags().BoolVar(&flags.runHooks, "run-hooks", false,
+		"run the commands defined by template migrations without asking for confirmation")
+	retval.Flags().BoolVar(&flags.noHooks, "no-hooks", false,
+		"don't run the commands defined by template migrations, or ask whether to run them")
+	retval.MarkFlagsMutuallyExclusive("run-hooks", "no-hooks")
+	return retval
+}
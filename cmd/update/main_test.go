@@ -0,0 +1,395 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/cmd/create"
+	"github.com/TheFriendlyCoder/rejigger/cmd/internal"
+	"github.com/TheFriendlyCoder/rejigger/cmd/shared"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/TheFriendlyCoder/rejigger/lib/templateManager"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const updatedManifest = `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 2.0
+template:
+  args:
+    - name: project_name
+      description: Name of the source code project
+    - name: license
+      description: License for the project
+      default: MIT
+`
+
+// templateFiles files in the first revision of the template the test projects are
+// generated from
+var templateFiles = map[string]string{
+	".rejig.yml": internal.SampleManifest,
+	"readme.txt": "Project {{project_name}}\nline 2\nline 3\nline 4\n",
+	"old.txt":    "old",
+	"keep.txt":   "keep",
+}
+
+// updateTemplate commits a new revision of the template the test projects are generated from
+func updateTemplate(r *require.Assertions, repoDir string) string {
+	hash, err := internal.CommitFiles(repoDir, map[string]string{
+		".rejig.yml": updatedManifest,
+		"readme.txt": "Project {{project_name}}\nline 2 updated\nline 3\nline 4\n",
+		"old.txt":    "",
+		"new.txt":    "License {{license}}",
+	})
+	r.NoError(err)
+	return hash
+}
+
+// runUpdate runs the update command against a project
+func runUpdate(projectDir string, extraArgs ...string) (string, error) {
+	return runUpdateWithInput(projectDir, "", extraArgs...)
+}
+
+// runUpdateWithInput runs the update command on a project, responding to any prompts
+// with the given input
+func runUpdateWithInput(projectDir string, input string, extraArgs ...string) (string, error) {
+	output := new(bytes.Buffer)
+	updateCmd := UpdateCmd()
+	updateCmd.SetOut(output)
+	updateCmd.SetErr(output)
+	updateCmd.SetIn(bytes.NewBufferString(input))
+	updateCmd.SetArgs(append([]string{projectDir}, extraArgs...))
+	err := updateCmd.ExecuteContext(context.TODO())
+	return output.String(), err
+}
+
+func Test_UpdateCommand(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project generated from a Git template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	repoDir, _, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+	r.NoError(err)
+
+	// with local changes made to the project
+	readmeFile := filepath.Join(projectDir, "readme.txt")
+	r.NoError(os.WriteFile(readmeFile, []byte("Project MyProj\nline 2\nline 3\nline 4 edited\n"), 0600))
+
+	// and a new revision of the template
+	expRevision := updateTemplate(r, repoDir)
+
+	// When we update the project
+	_, err = runUpdate(projectDir, "--no-input")
+	r.NoError(err)
+
+	// Then the changes made to the template should be merged into the project
+	contents, err := os.ReadFile(readmeFile)
+	r.NoError(err)
+	a.Equal("Project MyProj\nline 2 updated\nline 3\nline 4 edited\n", string(contents))
+	a.NoFileExists(filepath.Join(projectDir, "old.txt"))
+	a.FileExists(filepath.Join(projectDir, "keep.txt"))
+	contents, err = os.ReadFile(filepath.Join(projectDir, "new.txt"))
+	r.NoError(err)
+	a.Equal("License MIT", string(contents))
+
+	// and the archive should record the new revision of the template
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), projectDir)
+	r.NoError(err)
+	a.Equal(expRevision, archive.Template.Revision)
+	a.Equal("2.0", archive.Template.Version)
+	a.Equal(map[string]string{"project_name": "MyProj", "license": "MIT"}, archive.Args)
+
+	// And updating again should have no effect
+	output, err := runUpdate(projectDir, "--no-input")
+	r.NoError(err)
+	a.Contains(output, "already up to date")
+}
+
+func Test_UpdateCommandVersionConstraint(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a Git template with a tagged release
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "template")
+	hash, err := internal.CommitFiles(repoDir, map[string]string{
+		".rejig.yml": internal.SampleManifest,
+		"readme.txt": "Release 1.0 of {{project_name}}\n",
+	})
+	r.NoError(err)
+	r.NoError(internal.TagCommit(repoDir, "v1.0.0", hash))
+
+	// and a project generated from the template using a version constraint
+	projectDir := filepath.Join(tmpDir, "project")
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:    ao.TstGit,
+			Source:  "file://" + repoDir,
+			Name:    "MyTemplate",
+			Version: "^1.0",
+		}},
+	}
+	createCmd := create.CreateCmd()
+	createCmd.SetOut(new(bytes.Buffer))
+	createCmd.SetErr(new(bytes.Buffer))
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{projectDir, "MyTemplate", "--no-input", "--set", "project_name=MyProj"})
+	r.NoError(createCmd.ExecuteContext(ctx))
+
+	// and newer releases of the template, only some of which satisfy the constraint
+	expRevision := ""
+	for _, curRelease := range []string{"1.1", "2.0"} {
+		hash, err = internal.CommitFiles(repoDir, map[string]string{
+			"readme.txt": "Release " + curRelease + " of {{project_name}}\n",
+		})
+		r.NoError(err)
+		r.NoError(internal.TagCommit(repoDir, "v"+curRelease+".0", hash))
+		if curRelease == "1.1" {
+			expRevision = hash
+		}
+	}
+
+	// When we update the project
+	_, err = runUpdate(projectDir, "--no-input")
+	r.NoError(err)
+
+	// Then the project should be updated to the highest release satisfying the constraint
+	contents, err := os.ReadFile(filepath.Join(projectDir, "readme.txt"))
+	r.NoError(err)
+	a.Equal("Release 1.1 of MyProj\n", string(contents))
+
+	// and the archive should record the release which was used
+	archive, err := templateManager.ParseArchive(afero.NewOsFs(), projectDir)
+	r.NoError(err)
+	a.Equal("^1.0", archive.Template.Constraint)
+	a.Equal("v1.1.0", archive.Template.Tag)
+	a.Equal(expRevision, archive.Template.Revision)
+}
+
+func Test_UpdateCommandConflicts(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		args        []string
+		expContents string
+		expReject   bool
+	}{
+		"Conflict markers": {
+			args:        []string{"--no-input"},
+			expContents: "Project MyProj\n<<<<<<< project\nline 2 edited\n=======\nline 2 updated\n>>>>>>> template\nline 3\nline 4\n",
+		},
+		"Reject files": {
+			args:        []string{"--no-input", "--reject"},
+			expContents: "Project MyProj\nline 2 edited\nline 3\nline 4\n",
+			expReject:   true,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a project generated from a Git template
+			tmpDir, err := os.MkdirTemp("", "")
+			r.NoError(err)
+			defer os.RemoveAll(tmpDir)
+			repoDir, _, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+			r.NoError(err)
+
+			// with local changes which conflict with a new revision of the template
+			readmeFile := filepath.Join(projectDir, "readme.txt")
+			r.NoError(os.WriteFile(readmeFile, []byte("Project MyProj\nline 2 edited\nline 3\nline 4\n"), 0600))
+			updateTemplate(r, repoDir)
+
+			// When we update the project
+			_, err = runUpdate(projectDir, data.args...)
+
+			// Then the conflicting files should be reported
+			r.ErrorIs(err, e.NewMergeConflictError([]string{"readme.txt"}))
+
+			// and the conflicts should be recorded for the user to resolve
+			contents, err := os.ReadFile(readmeFile)
+			r.NoError(err)
+			a.Equal(data.expContents, string(contents))
+			if data.expReject {
+				contents, err = os.ReadFile(readmeFile + ".rej")
+				r.NoError(err)
+				a.Contains(string(contents), "-line 2\n")
+				a.Contains(string(contents), "+line 2 updated\n")
+			} else {
+				a.NoFileExists(readmeFile + ".rej")
+			}
+
+			// and non-conflicting changes should still be applied
+			a.FileExists(filepath.Join(projectDir, "new.txt"))
+		})
+	}
+}
+
+func Test_UpdateCommandLocalTemplate(t *testing.T) {
+	r := require.New(t)
+
+	// Given a project generated from a local template
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	projectDir := filepath.Join(tmpDir, "project")
+	appOptions := ao.AppOptions{
+		Templates: []ao.TemplateOptions{{
+			Type:   ao.TstLocal,
+			Source: internal.GetProjectDir(),
+			Name:   "MyTemplate",
+		}},
+	}
+	createCmd := create.CreateCmd()
+	createCmd.SetOut(new(bytes.Buffer))
+	createCmd.SetErr(new(bytes.Buffer))
+	ctx := context.WithValue(context.TODO(), shared.CkOptions, appOptions)
+	createCmd.SetArgs([]string{projectDir, "MyTemplate", "--no-input",
+		"--set", "project_name=MyProj", "--set", "version=1.0"})
+	r.NoError(createCmd.ExecuteContext(ctx))
+
+	// When we try to update the project
+	output, err := runUpdate(projectDir)
+
+	// Then the operation should fail
+	r.Error(err)
+	r.Contains(output, "does not support revisions")
+}
+
+func Test_UpdateCommandNoArchive(t *testing.T) {
+	r := require.New(t)
+
+	// Given a folder which wasn't generated by Rejigger
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// When we try to update the folder
+	_, err = runUpdate(tmpDir)
+
+	// Then the operation should fail
+	r.Error(err)
+}
+
+const migratingManifest = `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 2.0
+template:
+  args:
+    - name: project_name
+      description: Name of the source code project
+    - name: license
+      description: License for the project
+      default: MIT
+  migrations:
+    - version: 2.0
+      renames:
+        - from: readme.txt
+          to: docs/readme.txt
+      deletions:
+        - keep.txt
+      args:
+        license: GPL
+      hooks:
+        - "echo {{ license }}> hook.txt"
+`
+
+func Test_UpdateCommandMigrations(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		args    []string
+		input   string
+		expHook bool
+	}{
+		"Hooks requested": {
+			args:    []string{"--no-input", "--run-hooks"},
+			expHook: true,
+		},
+		"Hooks confirmed": {
+			args:    []string{},
+			input:   "y\n",
+			expHook: true,
+		},
+		"Hooks declined": {
+			args:    []string{},
+			input:   "n\n",
+			expHook: false,
+		},
+		"Hooks not requested": {
+			args:    []string{"--no-input"},
+			expHook: false,
+		},
+		"Hooks disabled": {
+			args:    []string{"--no-input", "--no-hooks"},
+			expHook: false,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a project generated from a Git template
+			tmpDir, err := os.MkdirTemp("", "")
+			r.NoError(err)
+			defer os.RemoveAll(tmpDir)
+			repoDir, _, projectDir, err := internal.NewProject(tmpDir, templateFiles)
+			r.NoError(err)
+
+			// with local changes made to the project
+			r.NoError(os.WriteFile(filepath.Join(projectDir, "readme.txt"),
+				[]byte("Project MyProj\nline 2\nline 3\nline 4 edited\n"), 0600))
+			r.NoError(os.WriteFile(filepath.Join(projectDir, "keep.txt"), []byte("edited"), 0600))
+
+			// and a new version of the template which moves and removes files
+			_, err = internal.CommitFiles(repoDir, map[string]string{
+				".rejig.yml":      migratingManifest,
+				"readme.txt":      "",
+				"keep.txt":        "",
+				"docs/readme.txt": "Project {{project_name}}\nline 2 updated\nline 3\nline 4\n",
+				"license.txt":     "License {{license}}",
+			})
+			r.NoError(err)
+
+			// When we update the project
+			output, err := runUpdateWithInput(projectDir, data.input, data.args...)
+			r.NoError(err, output)
+
+			// Then the local changes should follow the file to its new location
+			a.NoFileExists(filepath.Join(projectDir, "readme.txt"))
+			contents, err := os.ReadFile(filepath.Join(projectDir, "docs", "readme.txt"))
+			r.NoError(err)
+			a.Equal("Project MyProj\nline 2 updated\nline 3\nline 4 edited\n", string(contents))
+
+			// and deleted files should be removed even though they were modified
+			a.NoFileExists(filepath.Join(projectDir, "keep.txt"))
+
+			// and new args should use the values provided by the migration
+			contents, err = os.ReadFile(filepath.Join(projectDir, "license.txt"))
+			r.NoError(err)
+			a.Equal("License GPL", string(contents))
+
+			// and the migration hooks should only be run when requested or confirmed
+			if data.expHook {
+				contents, err = os.ReadFile(filepath.Join(projectDir, "hook.txt"))
+				r.NoError(err)
+				a.Contains(string(contents), "GPL")
+			} else {
+				a.NoFileExists(filepath.Join(projectDir, "hook.txt"))
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package applicationOptions
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/TheFriendlyCoder/rejigger/lib"
 	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -53,6 +55,9 @@ func (a AppOptions) validateInventory() []string {
 		if len(curInventory.Source) == 0 {
 			retval = append(retval, fmt.Sprintf("inventory %d source is undefined", i))
 		}
+		if len(curInventory.Ref) != 0 && curInventory.Type == IstLocal {
+			retval = append(retval, fmt.Sprintf("inventory %d ref is only supported by git inventories", i))
+		}
 	}
 
 	// Make sure the inventory names are all unique
@@ -64,22 +69,80 @@ func (a AppOptions) validateInventory() []string {
 	return retval
 }
 
-// parseInventory parses a template manifest file and returns a reference to
-// the parsed representation of the contents of the file
-func parseInventory(srcFS afero.Fs, path string) (InventoryData, error) {
+// problems gets a description of every way the templates defined in the inventory fail
+// to meet the requirements for the application
+func (d InventoryData) problems() []string {
+	var retval []string
+	allNames := map[string]int{}
+	for i, curTemplate := range d.Templates {
+		if len(curTemplate.Name) != 0 {
+			allNames[curTemplate.Name] += 1
+		}
+		if len(curTemplate.Version) == 0 {
+			continue
+		}
+		if len(curTemplate.Ref) != 0 {
+			retval = append(retval, fmt.Sprintf("template %d may not define both a ref and a version", i))
+		}
+		if _, err := lib.ParseVersionConstraint(curTemplate.Version); err != nil {
+			retval = append(retval, fmt.Sprintf("template %d version %s is invalid", i, curTemplate.Version))
+		}
+	}
+
+	// See if any template names are duplicated
+	for name, count := range allNames {
+		if count > 1 {
+			retval = append(retval, fmt.Sprintf("there are %d templates with the name %s", count, name))
+		}
+	}
+	return retval
+}
+
+// ValidateInventory parses a template inventory file in strict mode, returning an error
+// describing every problem found in the file
+func ValidateInventory(srcFS afero.Fs, path string) error {
+	_, err := parseInventory(srcFS, path, true)
+	return err
+}
+
+// parseInventory parses a template inventory file and returns a reference to
+// the parsed representation of the contents of the file. In strict mode, unknown keys,
+// values of the wrong type and missing required keys are reported as errors instead
+// of being ignored
+func parseInventory(srcFS afero.Fs, path string, strict bool) (InventoryData, error) {
 	var retval InventoryData
 	buf, err := afero.ReadFile(srcFS, path)
 	if err != nil {
 		return retval, errors.Wrap(err, "Failed to open inventory file")
 	}
 
-	// TODO: Find some way to get "Strict" mode to work properly (aka: KnownFields in v3)
+	if !strict {
+		err = yaml.Unmarshal(buf, &retval)
+		if err != nil {
+			return retval, errors.Wrap(err, "Failed to parse YAML content from inventory file")
+		}
+		return retval, nil
+	}
+
+	// yaml.v3 strict mode (aka: KnownFields) stops at the first problem, so we check
+	// the document structure ourselves
 	//		https://github.com/go-yaml/yaml/issues/460
-	//		https://github.com/go-yaml/yaml/issues/642
-	err = yaml.Unmarshal(buf, &retval)
-	if err != nil {
+	var node yaml.Node
+	if err = yaml.Unmarshal(buf, &node); err != nil {
 		return retval, errors.Wrap(err, "Failed to parse YAML content from inventory file")
 	}
+	messages := lib.CheckYAMLStructure(path, &node, reflect.TypeOf(retval))
 
+	// Values of the wrong type have already been reported, and the rest of the inventory
+	// is still decoded so it can be validated as well
+	err = yaml.Unmarshal(buf, &retval)
+	var typeErr *yaml.TypeError
+	if err != nil && !(errors.As(err, &typeErr) && len(messages) != 0) {
+		return retval, errors.Wrap(err, "Failed to parse YAML content from inventory file")
+	}
+	messages = append(messages, retval.problems()...)
+	if len(messages) != 0 {
+		return retval, e.NewInventoryError(messages)
+	}
 	return retval, nil
 }
@@ -11,7 +11,9 @@ import (
 // -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 //																				  Constants
 
-const inventoryFileName = ".rejig.inv.yml"
+// InventoryFileName name of the file, in the root folder of an inventory, which defines
+// the templates in the inventory
+const InventoryFileName = ".rejig.inv.yml"
 
 // -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 //														    			InventorySourceType
@@ -71,6 +73,11 @@ type InventoryOptions struct {
 	Source string
 	// Namespace prefix to add to all templates contained in this inventory
 	Namespace string
+	// Ref optional branch name, tag name or commit hash identifying the revision of the
+	// inventory to use, for inventories stored in Git repositories. Templates defined in
+	// the inventory use the same revision unless they define their own. If not provided,
+	// the default branch of the repository is used
+	Ref string
 }
 
 // GetNamespace friendly name associated with the namespace. Used when referring to templates
@@ -96,7 +103,8 @@ func (i *InventoryOptions) GetFilesystem() (afero.Fs, error) {
 	case IstLocal:
 		return afero.NewOsFs(), nil
 	case IstGit:
-		return lib.GetGitFilesystem(i.Source)
+		retval, _, err := lib.CloneGitRepository(i.Source, i.Ref)
+		return retval, err
 	case IstUnknown:
 		fallthrough
 	case IstUndefined:
@@ -126,7 +134,7 @@ func (i *InventoryOptions) GetRoot() string {
 // GetInventoryFile gets the path, relative to the filesystem root, where the inventory definition
 // file is found
 func (i *InventoryOptions) GetInventoryFile() string {
-	return path.Join(i.GetRoot(), inventoryFileName)
+	return path.Join(i.GetRoot(), InventoryFileName)
 }
 
 // GetTemplateDefinitions gets a list of all templates defined in this inventory
@@ -144,7 +152,7 @@ func (i *InventoryOptions) GetTemplateDefinitions() ([]TemplateOptions, error) {
 	}
 
 	// TODO: Cache these results so they can be reused
-	inventory, err := parseInventory(fileSystem, inventoryPath)
+	inventory, err := parseInventory(fileSystem, inventoryPath, false)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +166,16 @@ func (i *InventoryOptions) GetTemplateDefinitions() ([]TemplateOptions, error) {
 			SubDir: curTemplate.GetSource(),
 			Source: i.GetSource(),
 			// TODO: Consider setting name to i.Namespace + "." + curTemplate.Name
-			Name: curTemplate.Name,
+			Name:        curTemplate.Name,
+			Description: curTemplate.Description,
+			Tags:        curTemplate.Tags,
+			Maintainer:  curTemplate.Maintainer,
+			Language:    curTemplate.Language,
+			Ref:         curTemplate.Ref,
+			Version:     curTemplate.Version,
+		}
+		if len(temp.Ref) == 0 && len(temp.Version) == 0 {
+			temp.Ref = i.Ref
 		}
 		retval = append(retval, temp)
 	}
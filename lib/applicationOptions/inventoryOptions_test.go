@@ -6,6 +6,7 @@ import (
 	"path"
 	"testing"
 
+	"github.com/TheFriendlyCoder/rejigger/lib/internal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -208,7 +209,7 @@ func Test_getLocalTemplateDefinitions(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// And a sample config file
-	outputFile := path.Join(tmpDir, inventoryFileName)
+	outputFile := path.Join(tmpDir, InventoryFileName)
 	expName := "test1"
 	expNamespace := "FuBar"
 	expType := TstLocal
@@ -248,7 +249,7 @@ func Test_getLocalTemplateDefinitionsFailures(t *testing.T) {
 
 	subdir := path.Join(tmpDir, "sample")
 	r.NoError(os.Mkdir(subdir, 0700))
-	outputFile := path.Join(subdir, inventoryFileName)
+	outputFile := path.Join(subdir, InventoryFileName)
 	r.NoError(os.WriteFile(outputFile, []byte("this isn't valid YAML !!?!"), 0600))
 
 	tests := map[string]struct {
@@ -287,3 +288,46 @@ func Test_getLocalTemplateDefinitionsFailures(t *testing.T) {
 		})
 	}
 }
+
+func Test_getGitTemplateDefinitionsRef(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a Git inventory with a tagged release followed by newer changes
+	repoDir := t.TempDir()
+	repo, _, commit := internal.NewGitRepo(r, repoDir, InventoryFileName)
+	released := commit(`
+templates:
+  - name: inherited
+    source: ./inherited
+  - name: pinned
+    source: ./pinned
+    ref: stable
+`)
+	_, err := repo.CreateTag("v1.0", released, nil)
+	r.NoError(err)
+	commit(`
+templates:
+  - name: unreleased
+    source: ./unreleased
+`)
+
+	// When we load the template definitions from the tagged release of the inventory
+	inv := InventoryOptions{
+		Type:      IstGit,
+		Namespace: "FuBar",
+		Source:    "file://" + repoDir,
+		Ref:       "v1.0",
+	}
+	opts, err := inv.GetTemplateDefinitions()
+	r.NoError(err)
+
+	// Then the templates defined in the release should be found
+	r.Len(opts, 2)
+	a.Equal("inherited", opts[0].GetName())
+	a.Equal("pinned", opts[1].GetName())
+
+	// and the templates should use the same revision as the inventory unless they define their own
+	a.Equal("v1.0", opts[0].Ref)
+	a.Equal("stable", opts[1].Ref)
+}
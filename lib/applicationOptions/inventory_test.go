@@ -6,6 +6,7 @@ import (
 	"path"
 	"testing"
 
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,7 +41,7 @@ templates:
 
 	// When we try parsing in the inventory
 	fs := afero.NewOsFs()
-	result, err := parseInventory(fs, outputFile)
+	result, err := parseInventory(fs, outputFile, false)
 
 	// The inventory should be successfully parsed
 	r.NoError(err)
@@ -49,3 +50,36 @@ templates:
 	a.Equal(expSource, result.Templates[0].GetSource())
 	a.Equal(expName, result.Templates[0].GetName())
 }
+
+func Test_ValidateInventory(t *testing.T) {
+	r := require.New(t)
+
+	// Given an inventory file containing several typos
+	srcFS := afero.NewMemMapFs()
+	invData := `templates:
+  - name: first
+    source: first
+    sub_dir: docs
+  - source: second
+  - name: third
+    source: [a, b]
+  - name: first
+    source: fourth
+    ref: main
+    version: ^1.0
+`
+	r.NoError(afero.WriteFile(srcFS, "/.rejig.inv.yml", []byte(invData), 0644))
+
+	// When we validate the inventory
+	err := ValidateInventory(srcFS, "/.rejig.inv.yml")
+
+	// Then every problem should be reported in a single error, along with its location
+	// when it is known
+	r.ErrorIs(err, e.NewInventoryError([]string{
+		"/.rejig.inv.yml:4:5: unknown key templates[0].sub_dir",
+		"/.rejig.inv.yml:5:5: missing required key templates[1].name",
+		"/.rejig.inv.yml:7:13: expected a single value for templates[2].source",
+		"template 3 may not define both a ref and a version",
+		"there are 2 templates with the name first",
+	}))
+}
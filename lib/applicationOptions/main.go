@@ -2,6 +2,7 @@ package applicationOptions
 
 import (
 	"reflect"
+	"strings"
 
 	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/mitchellh/mapstructure"
@@ -55,6 +56,65 @@ func (a AppOptions) FindInventory(namespace string) *InventoryOptions {
 	return nil
 }
 
+// TemplateEntry describes a template which can be referenced from the command line
+type TemplateEntry struct {
+	// QualifiedName name used to refer to the template from the command line, which is
+	// prefixed by the namespace of the inventory the template was defined in, if any
+	QualifiedName string
+	// Template options describing where the template is found
+	Template TemplateOptions
+}
+
+// Matches checks to see whether a template matches a search query. The query is matched,
+// ignoring case, against the qualified name, description and tags of the template
+func (t TemplateEntry) Matches(query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(t.QualifiedName), query) ||
+		strings.Contains(strings.ToLower(t.Template.Description), query) {
+		return true
+	}
+	for _, curTag := range t.Template.Tags {
+		if strings.Contains(strings.ToLower(curTag), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// InventoryFailure describes an inventory whose template definitions could not be loaded
+type InventoryFailure struct {
+	// Namespace namespace of the inventory that failed to load
+	Namespace string
+	// Err error reported when loading the inventory
+	Err error
+}
+
+// ListTemplates gets all templates defined in the application options, followed by all
+// templates defined in every inventory. Inventories which fail to load are reported
+// separately, so templates from other inventories are still listed
+func (a AppOptions) ListTemplates() ([]TemplateEntry, []InventoryFailure) {
+	var retval []TemplateEntry
+	var failures []InventoryFailure
+	for _, curTemplate := range a.Templates {
+		retval = append(retval, TemplateEntry{QualifiedName: curTemplate.GetName(), Template: curTemplate})
+	}
+	for i := range a.Inventories {
+		curInventory := &a.Inventories[i]
+		templates, err := curInventory.GetTemplateDefinitions()
+		if err != nil {
+			failures = append(failures, InventoryFailure{Namespace: curInventory.GetNamespace(), Err: err})
+			continue
+		}
+		for _, curTemplate := range templates {
+			retval = append(retval, TemplateEntry{
+				QualifiedName: curInventory.GetNamespace() + "." + curTemplate.GetName(),
+				Template:      curTemplate,
+			})
+		}
+	}
+	return retval, failures
+}
+
 // appOptionsDecoder custom hook method used to translate raw config data into a structure
 // that is easier to leverage in the application code
 func appOptionsDecoder() mapstructure.DecodeHookFuncType {
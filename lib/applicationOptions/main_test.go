@@ -112,6 +112,55 @@ func Test_validationFailures(t *testing.T) {
 					Type:   TstLocal,
 				}},
 		},
+		"Template invalid exclusion": {
+			templateOptions: []TemplateOptions{{
+				Name:       "My Template",
+				Source:     "/tmp/location",
+				Type:       TstLocal,
+				Exclusions: []string{"**/notvalid/*?."},
+			}},
+		},
+		"Template ref for local template": {
+			templateOptions: []TemplateOptions{{
+				Name:   "My Template",
+				Source: "/tmp/location",
+				Type:   TstLocal,
+				Ref:    "v1.0",
+			}},
+		},
+		"Template version for local template": {
+			templateOptions: []TemplateOptions{{
+				Name:    "My Template",
+				Source:  "/tmp/location",
+				Type:    TstLocal,
+				Version: "^1.0",
+			}},
+		},
+		"Template ref and version": {
+			templateOptions: []TemplateOptions{{
+				Name:    "My Template",
+				Source:  "https://some/location",
+				Type:    TstGit,
+				Ref:     "main",
+				Version: "^1.0",
+			}},
+		},
+		"Template invalid version": {
+			templateOptions: []TemplateOptions{{
+				Name:    "My Template",
+				Source:  "https://some/location",
+				Type:    TstGit,
+				Version: "^fubar",
+			}},
+		},
+		"Inventory ref for local inventory": {
+			inventoryOptions: []InventoryOptions{{
+				Namespace: "Fubar",
+				Source:    "/tmp/location",
+				Type:      IstLocal,
+				Ref:       "v1.0",
+			}},
+		},
 		"Inventory missing type": {
 			inventoryOptions: []InventoryOptions{{
 				Namespace: "Fubar",
@@ -446,3 +495,89 @@ func Test_findInventoryByName(t *testing.T) {
 	result := appoptions.FindInventory(expectedNamespace)
 	require.Equal(t, expectedInv, *result)
 }
+
+func Test_listTemplates(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a local inventory
+	tmpDir := t.TempDir()
+	invData := `
+templates:
+  - name: first
+    source: ./first
+    description: The first template
+    tags: [cli, go]
+    maintainer: Tools Team
+    language: go
+`
+	r.NoError(os.WriteFile(path.Join(tmpDir, InventoryFileName), []byte(invData), 0600))
+
+	// and app options referencing the inventory, a missing inventory and a template
+	appOptions := AppOptions{
+		Templates: []TemplateOptions{{
+			Type:   TstLocal,
+			Source: "/path/to/template",
+			Name:   "local",
+		}},
+		Inventories: []InventoryOptions{
+			{Type: IstLocal, Source: path.Join(tmpDir, "missing"), Namespace: "broken"},
+			{Type: IstLocal, Source: tmpDir, Namespace: "inv"},
+		},
+	}
+
+	// When we list the available templates
+	templates, failures := appOptions.ListTemplates()
+
+	// Then templates from every inventory which could be loaded should be listed
+	r.Len(templates, 2)
+	a.Equal("local", templates[0].QualifiedName)
+	a.Equal("inv.first", templates[1].QualifiedName)
+	a.Equal("The first template", templates[1].Template.Description)
+	a.Equal([]string{"cli", "go"}, templates[1].Template.Tags)
+	a.Equal("Tools Team", templates[1].Template.Maintainer)
+	a.Equal("go", templates[1].Template.Language)
+
+	// and inventories which couldn't be loaded should be reported
+	r.Len(failures, 1)
+	a.Equal("broken", failures[0].Namespace)
+	a.Error(failures[0].Err)
+}
+
+func Test_templateEntryMatches(t *testing.T) {
+	a := assert.New(t)
+
+	// Given a template with metadata
+	entry := TemplateEntry{
+		QualifiedName: "inv.goService",
+		Template: TemplateOptions{
+			Name:        "goService",
+			Description: "Microservice with a REST API",
+			Tags:        []string{"backend", "Docker"},
+			Maintainer:  "Platform Team",
+			Language:    "go",
+		},
+	}
+
+	tests := map[string]struct {
+		query    string
+		expected bool
+	}{
+		"Matches name":        {query: "service", expected: true},
+		"Matches namespace":   {query: "inv.", expected: true},
+		"Matches description": {query: "rest api", expected: true},
+		"Matches tag":         {query: "docker", expected: true},
+		"Ignores maintainer":  {query: "platform", expected: false},
+		"No match":            {query: "python", expected: false},
+		"Matches partial tag": {query: "back", expected: true},
+		"Matches empty query": {query: "", expected: true},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// When we check the template against the query
+			// Then the result should match the expected outcome
+			a.Equal(data.expected, entry.Matches(data.query))
+		})
+	}
+}
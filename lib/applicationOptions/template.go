@@ -3,6 +3,7 @@ package applicationOptions
 import (
 	"fmt"
 
+	"github.com/TheFriendlyCoder/rejigger/lib"
 	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 )
 
@@ -43,6 +44,25 @@ func (a AppOptions) validateTemplates() []string {
 		if len(curTemplate.GetSource()) == 0 {
 			retval = append(retval, fmt.Sprintf("template %d source is undefined", i))
 		}
+		if len(curTemplate.Ref) != 0 && curTemplate.Type == TstLocal {
+			retval = append(retval, fmt.Sprintf("template %d ref is only supported by git templates", i))
+		}
+		if len(curTemplate.Version) != 0 {
+			if curTemplate.Type == TstLocal {
+				retval = append(retval, fmt.Sprintf("template %d version is only supported by git templates", i))
+			}
+			if len(curTemplate.Ref) != 0 {
+				retval = append(retval, fmt.Sprintf("template %d may not define both a ref and a version", i))
+			}
+			if _, err := lib.ParseVersionConstraint(curTemplate.Version); err != nil {
+				retval = append(retval, fmt.Sprintf("template %d version %s is invalid", i, curTemplate.Version))
+			}
+		}
+		for _, curExclusion := range curTemplate.Exclusions {
+			if _, err := lib.CompileExclusion(curExclusion); err != nil {
+				retval = append(retval, fmt.Sprintf("template %d exclusion %s is invalid", i, curExclusion))
+			}
+		}
 	}
 
 	// See if any template names are duplicated
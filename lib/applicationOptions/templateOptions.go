@@ -1,12 +1,14 @@
 package applicationOptions
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"regexp"
 	"strings"
 
 	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
@@ -64,6 +66,17 @@ func (t *TemplateSourceType) fromString(value string) {
 	}
 }
 
+// String Converts the value from our enumeration to a string representation, for use
+// when displaying template details
+func (t TemplateSourceType) String() string {
+	return t.toString()
+}
+
+// MarshalYAML encodes values for our enumeration as YAML content
+func (t TemplateSourceType) MarshalYAML() (interface{}, error) {
+	return t.toString(), nil
+}
+
 // UnmarshalYAML decodes values for our enumeration from YAML content
 func (t *TemplateSourceType) UnmarshalYAML(value *yaml.Node) error {
 	var temp string
@@ -82,20 +95,42 @@ type TemplateOptions struct {
 	// Type identifier describing the protocol to use when retrieving template content
 	Type TemplateSourceType
 	// Source Path or URL where the source template can be found
-	Source string `yaml:"source"` // TODO: make this member private
+	Source string `yaml:"source" required:"true"` // TODO: make this member private
 	// Name friendly name associated with the template. Used when referring to the template
 	// from the command line
-	Name string `yaml:"name"`
+	Name string `yaml:"name" required:"true"`
+	// Description optional descriptive text explaining the purpose of the template
+	Description string `yaml:"description"`
+	// Tags optional keywords used to find the template when searching
+	Tags []string `yaml:"tags"`
+	// Maintainer optional name or contact details for the person or team who maintains
+	// the template
+	Maintainer string `yaml:"maintainer"`
+	// Language optional name of the programming language used by projects generated
+	// from the template
+	Language string `yaml:"language"`
 	// SubDir optional sub-directory under the template Source location where the template
 	// definition is found. If not provided, the template is expected to exist in the root
 	// folder of the Source location
 	SubDir string `yaml:"subdir"`
-	// Exclusions set of 0 or more regular expressions defining files to be excluded from
-	// template processing
+	// Ref optional branch name, tag name or commit hash identifying the revision of the
+	// template to use, for templates stored in Git repositories. If not provided, the
+	// default branch of the repository is used
+	Ref string `yaml:"ref"`
+	// Version optional constraint, like "^2.1" or ">= 1.0, < 2.0", identifying the releases
+	// of the template which may be used, for templates stored in Git repositories. The tag
+	// for the highest release satisfying the constraint is used. May not be combined with Ref
+	Version string `yaml:"version"`
+	// Exclusions set of 0 or more regular expressions or gitignore-style globs (when prefixed
+	// with "glob:") defining files to be excluded from template processing. Globs are matched
+	// against paths relative to the root folder of the template, and regular expressions are
+	// matched against both the relative path and the full path of each file
 	Exclusions []string `yaml:"exclusions"`
 
 	// regexExclusions cache of pre-compiled regular expressions built from the Exclusions list
 	regexExclusions []*regexp.Regexp
+	// globExclusions flags identifying the entries in regexExclusions built from globs
+	globExclusions []bool
 }
 
 // buildRegex populates the regexExclusions cache in the TemplateOptions struct
@@ -106,28 +141,37 @@ func (t *TemplateOptions) buildRegex() error {
 	}
 
 	ignoreList := make([]*regexp.Regexp, 0, len(t.Exclusions))
+	globList := make([]bool, 0, len(t.Exclusions))
 	for _, curExpr := range t.Exclusions {
-		curIgnore, err := regexp.Compile(curExpr)
+		curIgnore, err := lib.CompileExclusion(curExpr)
 		if err != nil {
-			return errors.WithStack(err)
+			return err
 		}
 		ignoreList = append(ignoreList, curIgnore)
+		globList = append(globList, strings.HasPrefix(curExpr, lib.GlobExclusionPrefix))
 	}
 	t.regexExclusions = ignoreList
+	t.globExclusions = globList
 	return nil
 }
 
-// IsFileExcluded returns true if the given file path should be excluded based on the
-// exclusion rules provided by the template options, false if not
-func (t *TemplateOptions) IsFileExcluded(curFile string) bool {
+// IsFileExcluded returns true if a file should be excluded based on the exclusion rules
+// provided by the template options, false if not. filePath is the full path to the file
+// and relPath is its slash separated path relative to the template root. Regular
+// expressions are matched against either path, so expressions written for full paths
+// keep working, while globs are only matched against the relative path
+func (t *TemplateOptions) IsFileExcluded(filePath string, relPath string) bool {
 	// TODO: move this regex builder into object creation to avoid having a panic here
 	err := t.buildRegex()
 	if err != nil {
 		panic(err.Error())
 	}
 
-	for _, curIgnore := range t.regexExclusions {
-		if curIgnore.Match([]byte(curFile)) {
+	for i, curIgnore := range t.regexExclusions {
+		if curIgnore.MatchString(relPath) {
+			return true
+		}
+		if !t.globExclusions[i] && curIgnore.MatchString(filePath) {
 			return true
 		}
 	}
@@ -165,11 +209,32 @@ func (t *TemplateOptions) GetType() TemplateSourceType {
 
 // GetFilesystem Gets a virtual filesystem pre-loaded to point to the file system for the template
 func (t *TemplateOptions) GetFilesystem() (afero.Fs, error) {
+	retval, _, err := t.GetFilesystemRevision("")
+	return retval, err
+}
+
+// GetFilesystemRevision Gets a virtual filesystem pre-loaded to point to the file system for the
+// template, along with an identifier for the revision of the template that was loaded. If a
+// revision is given, that specific revision of the template is loaded. Otherwise, the release
+// matching the template Version, or the revision identified by the template Ref, is loaded.
+// The revision is always empty for template sources which don't support revisions
+func (t *TemplateOptions) GetFilesystemRevision(revision string) (afero.Fs, string, error) {
 	switch t.Type {
 	case TstLocal:
-		return afero.NewOsFs(), nil
+		if revision != "" || t.Ref != "" || t.Version != "" {
+			return nil, "", e.NewSimpleError("Revisions are not supported by local template " + t.Name)
+		}
+		return afero.NewOsFs(), "", nil
 	case TstGit:
-		return lib.GetGitFilesystem(t.Source)
+		if revision == "" && t.Version != "" {
+			var err error
+			if revision, err = t.ResolveVersion(); err != nil {
+				return nil, "", err
+			}
+		} else if revision == "" {
+			revision = t.Ref
+		}
+		return lib.CloneGitRepository(t.Source, revision)
 	case TstUnknown:
 		fallthrough
 	case TstUndefined:
@@ -179,6 +244,24 @@ func (t *TemplateOptions) GetFilesystem() (afero.Fs, error) {
 	}
 }
 
+// ResolveVersion gets the name of the tag identifying the highest release of the template
+// which satisfies the template Version constraint
+func (t *TemplateOptions) ResolveVersion() (string, error) {
+	constraint, err := lib.ParseVersionConstraint(t.Version)
+	if err != nil {
+		return "", err
+	}
+	tags, err := lib.ListGitTags(t.Source)
+	if err != nil {
+		return "", err
+	}
+	retval, found := lib.SelectVersionTag(tags, constraint)
+	if !found {
+		return "", e.NewSimpleError(fmt.Sprintf("No release of template %s matches version %s", t.Name, t.Version))
+	}
+	return retval, nil
+}
+
 // GetProjectRoot gets the path to the root folder of the virtual file system associated with
 // this template
 func (t *TemplateOptions) GetProjectRoot() string {
@@ -124,6 +124,26 @@ func Test_TemplateOptionsIsFileExcluded(t *testing.T) {
 			result:     false,
 			filename:   "some/path/other.txt/folder",
 		},
+		"Glob matches": {
+			exclusions: []string{"glob:*.txt"},
+			result:     true,
+			filename:   "some/path/other.txt",
+		},
+		"Glob doesn't match": {
+			exclusions: []string{"glob:/other.txt"},
+			result:     false,
+			filename:   "some/path/other.txt",
+		},
+		"Regex matches full path": {
+			exclusions: []string{"^/src/template/docs/"},
+			result:     true,
+			filename:   "docs/readme.md",
+		},
+		"Glob ignores full path": {
+			exclusions: []string{"glob:template"},
+			result:     false,
+			filename:   "main.txt",
+		},
 	}
 
 	for name, data := range tests {
@@ -134,7 +154,7 @@ func Test_TemplateOptionsIsFileExcluded(t *testing.T) {
 				Type:       TstLocal,
 				Exclusions: data.exclusions,
 			}
-			r.Equal(data.result, opts.IsFileExcluded(data.filename))
+			r.Equal(data.result, opts.IsFileExcluded("/src/template/"+data.filename, data.filename))
 		})
 	}
 }
@@ -148,7 +168,7 @@ func Test_TemplateOptionsIsFileExcludedInvalidRegex(t *testing.T) {
 		Type:       TstLocal,
 		Exclusions: []string{"**/notvalid/*?."},
 	}
-	r.Panics(func() { opts.IsFileExcluded("main.txt") })
+	r.Panics(func() { opts.IsFileExcluded("/src/template/main.txt", "main.txt") })
 }
 
 func Test_TemplateOptionsGetSourceHomeFolder(t *testing.T) {
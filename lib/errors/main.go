@@ -44,6 +44,194 @@ func NewAppOptionsError(messages []string) error {
 	return errors.WithStack(appOptionsError{messages})
 }
 
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										ManifestError
+
+type manifestError struct {
+	Messages []string
+}
+
+func (e manifestError) Error() string {
+	if len(e.Messages) == 1 {
+		return "Invalid template manifest: " + e.Messages[0]
+	}
+	retval := strings.Join(e.Messages, "\n\t")
+	return "Invalid template manifest:\n\t" + retval
+}
+
+func (e manifestError) Is(other error) bool {
+	var newVal manifestError
+	if errors.As(other, &newVal) {
+		if len(e.Messages) != len(newVal.Messages) {
+			return false
+		}
+		for i, curMessage := range newVal.Messages {
+			if e.Messages[i] != curMessage {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func NewManifestError(messages []string) error {
+	return errors.WithStack(manifestError{messages})
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										InventoryError
+
+type inventoryError struct {
+	Messages []string
+}
+
+func (e inventoryError) Error() string {
+	if len(e.Messages) == 1 {
+		return "Invalid template inventory: " + e.Messages[0]
+	}
+	retval := strings.Join(e.Messages, "\n\t")
+	return "Invalid template inventory:\n\t" + retval
+}
+
+func (e inventoryError) Is(other error) bool {
+	var newVal inventoryError
+	if errors.As(other, &newVal) {
+		if len(e.Messages) != len(newVal.Messages) {
+			return false
+		}
+		for i, curMessage := range newVal.Messages {
+			if e.Messages[i] != curMessage {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func NewInventoryError(messages []string) error {
+	return errors.WithStack(inventoryError{messages})
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										MissingArgsError
+
+type missingArgsError struct {
+	ArgNames []string
+}
+
+func (e missingArgsError) Error() string {
+	return "No values provided for required template args:\n\t" + strings.Join(e.ArgNames, "\n\t")
+}
+
+func (e missingArgsError) Is(other error) bool {
+	var newVal missingArgsError
+	if errors.As(other, &newVal) {
+		if len(e.ArgNames) != len(newVal.ArgNames) {
+			return false
+		}
+		for i, curName := range newVal.ArgNames {
+			if e.ArgNames[i] != curName {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func NewMissingArgsError(argNames []string) error {
+	return errors.WithStack(missingArgsError{argNames})
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										FileConflictError
+
+type fileConflictError struct {
+	Paths []string
+}
+
+func (e fileConflictError) Error() string {
+	return "Generated files conflict with existing files:\n\t" + strings.Join(e.Paths, "\n\t")
+}
+
+func (e fileConflictError) Is(other error) bool {
+	var newVal fileConflictError
+	if errors.As(other, &newVal) {
+		if len(e.Paths) != len(newVal.Paths) {
+			return false
+		}
+		for i, curPath := range newVal.Paths {
+			if e.Paths[i] != curPath {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func NewFileConflictError(paths []string) error {
+	return errors.WithStack(fileConflictError{paths})
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										MergeConflictError
+
+type mergeConflictError struct {
+	Paths []string
+}
+
+func (e mergeConflictError) Error() string {
+	return "Unable to merge template changes into files:\n\t" + strings.Join(e.Paths, "\n\t")
+}
+
+func (e mergeConflictError) Is(other error) bool {
+	var newVal mergeConflictError
+	if errors.As(other, &newVal) {
+		if len(e.Paths) != len(newVal.Paths) {
+			return false
+		}
+		for i, curPath := range newVal.Paths {
+			if e.Paths[i] != curPath {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func NewMergeConflictError(paths []string) error {
+	return errors.WithStack(mergeConflictError{paths})
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//										UnsupportedAppVersionError
+
+type unsupportedAppVersionError struct {
+	Required string
+	Current  string
+}
+
+func (e unsupportedAppVersionError) Error() string {
+	return "Template requires Rejigger version " + e.Required + " or newer, but version " + e.Current +
+		" is installed. Please install Rejigger " + e.Required + " or newer to use this template"
+}
+
+func (e unsupportedAppVersionError) Is(other error) bool {
+	var newVal unsupportedAppVersionError
+	if errors.As(other, &newVal) {
+		return e.Required == newVal.Required && e.Current == newVal.Current
+	}
+	return false
+}
+
+func NewUnsupportedAppVersionError(required string, current string) error {
+	return errors.WithStack(unsupportedAppVersionError{required, current})
+}
+
 // -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 //									PathError
 
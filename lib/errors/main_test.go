@@ -32,6 +32,30 @@ func Test_CheckErrorIsWorking(t *testing.T) {
 			srcType:  NewAppOptionsError([]string{"My Error", "Other Error"}),
 			destType: NewAppOptionsError([]string{"My Error", "Other Error"}),
 		},
+		"Check manifestError": {
+			srcType:  NewManifestError([]string{"My Error", "Other Error"}),
+			destType: NewManifestError([]string{"My Error", "Other Error"}),
+		},
+		"Check inventoryError": {
+			srcType:  NewInventoryError([]string{"My Error", "Other Error"}),
+			destType: NewInventoryError([]string{"My Error", "Other Error"}),
+		},
+		"Check missingArgsError": {
+			srcType:  NewMissingArgsError([]string{"arg1", "arg2"}),
+			destType: NewMissingArgsError([]string{"arg1", "arg2"}),
+		},
+		"Check fileConflictError": {
+			srcType:  NewFileConflictError([]string{"file1", "file2"}),
+			destType: NewFileConflictError([]string{"file1", "file2"}),
+		},
+		"Check mergeConflictError": {
+			srcType:  NewMergeConflictError([]string{"file1", "file2"}),
+			destType: NewMergeConflictError([]string{"file1", "file2"}),
+		},
+		"Check unsupportedAppVersionError": {
+			srcType:  NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+			destType: NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+		},
 		"Check pathError": {
 			srcType:  NewPathError("My Path", PePathNotFound),
 			destType: NewPathError("My Path", PePathNotFound),
@@ -88,6 +112,39 @@ func Test_CheckErrorMessages(t *testing.T) {
 			srcType:    NewAppOptionsError([]string{"My Error"}),
 			expMessage: "Failed to parse application option: My Error",
 		},
+		"Check manifestError multiple messages": {
+			srcType:    NewManifestError([]string{"My Error", "Other Error"}),
+			expMessage: "Invalid template manifest:\n\tMy Error\n\tOther Error",
+		},
+		"Check manifestError single message": {
+			srcType:    NewManifestError([]string{"My Error"}),
+			expMessage: "Invalid template manifest: My Error",
+		},
+		"Check inventoryError multiple messages": {
+			srcType:    NewInventoryError([]string{"My Error", "Other Error"}),
+			expMessage: "Invalid template inventory:\n\tMy Error\n\tOther Error",
+		},
+		"Check inventoryError single message": {
+			srcType:    NewInventoryError([]string{"My Error"}),
+			expMessage: "Invalid template inventory: My Error",
+		},
+		"Check missingArgsError": {
+			srcType:    NewMissingArgsError([]string{"arg1", "arg2"}),
+			expMessage: "No values provided for required template args:\n\targ1\n\targ2",
+		},
+		"Check fileConflictError": {
+			srcType:    NewFileConflictError([]string{"file1", "file2"}),
+			expMessage: "Generated files conflict with existing files:\n\tfile1\n\tfile2",
+		},
+		"Check mergeConflictError": {
+			srcType:    NewMergeConflictError([]string{"file1", "file2"}),
+			expMessage: "Unable to merge template changes into files:\n\tfile1\n\tfile2",
+		},
+		"Check unsupportedAppVersionError": {
+			srcType: NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+			expMessage: "Template requires Rejigger version 1.2.0 or newer, but version 1.0.0 is installed. " +
+				"Please install Rejigger 1.2.0 or newer to use this template",
+		},
 		"Check pathError path not found": {
 			srcType:    NewPathError("My Path", PePathNotFound),
 			expMessage: "Path not found: My Path",
@@ -162,6 +219,54 @@ func Test_CheckNotErrorIsWorking(t *testing.T) {
 			srcType:  NewAppOptionsError([]string{"My Error", "Other Error"}),
 			destType: NewAppOptionsError([]string{"My Error"}),
 		},
+		"Compare manifestError to fake error": {
+			srcType:  NewManifestError([]string{"My Error", "Other Error"}),
+			destType: fakeErr,
+		},
+		"Compare manifestError to different messages": {
+			srcType:  NewManifestError([]string{"My Error", "Other Error"}),
+			destType: NewManifestError([]string{"My Error"}),
+		},
+		"Compare inventoryError to fake error": {
+			srcType:  NewInventoryError([]string{"My Error", "Other Error"}),
+			destType: fakeErr,
+		},
+		"Compare inventoryError to different messages": {
+			srcType:  NewInventoryError([]string{"My Error", "Other Error"}),
+			destType: NewInventoryError([]string{"My Error"}),
+		},
+		"Compare missingArgsError to fake error": {
+			srcType:  NewMissingArgsError([]string{"arg1", "arg2"}),
+			destType: fakeErr,
+		},
+		"Compare missingArgsError to different args": {
+			srcType:  NewMissingArgsError([]string{"arg1", "arg2"}),
+			destType: NewMissingArgsError([]string{"arg1", "arg3"}),
+		},
+		"Compare fileConflictError to fake error": {
+			srcType:  NewFileConflictError([]string{"file1", "file2"}),
+			destType: fakeErr,
+		},
+		"Compare fileConflictError to different paths": {
+			srcType:  NewFileConflictError([]string{"file1", "file2"}),
+			destType: NewFileConflictError([]string{"file1", "file3"}),
+		},
+		"Compare mergeConflictError to fake error": {
+			srcType:  NewMergeConflictError([]string{"file1", "file2"}),
+			destType: fakeErr,
+		},
+		"Compare mergeConflictError to different paths": {
+			srcType:  NewMergeConflictError([]string{"file1", "file2"}),
+			destType: NewMergeConflictError([]string{"file1", "file3"}),
+		},
+		"Compare unsupportedAppVersionError to fake error": {
+			srcType:  NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+			destType: fakeErr,
+		},
+		"Compare unsupportedAppVersionError to different versions": {
+			srcType:  NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+			destType: NewUnsupportedAppVersionError("1.3.0", "1.0.0"),
+		},
 		"Compare pathError to fake error": {
 			srcType:  NewPathError("My Path", PePathNotFound),
 			destType: fakeErr,
@@ -0,0 +1,212 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// GitCache describes a folder on disk where copies of remote Git repositories are kept,
+// so they only need to be downloaded once and are updated incrementally afterwards
+type GitCache struct {
+	// Dir path to the folder where cached repositories are stored
+	Dir string
+	// Offline indicates that cached repositories should be used as-is, without
+	// checking the remote repositories for changes
+	Offline bool
+}
+
+// gitCache cache used when loading remote Git repositories. Repositories are cloned
+// in memory every time they are used when no cache is configured
+var gitCache *GitCache
+
+// fetchedRepos cached repositories that have already been updated by this process, so
+// repositories used several times in a single run are only fetched once
+var fetchedRepos = map[string]bool{}
+
+// SetGitCache configures the cache used when loading remote Git repositories. Caching
+// is disabled when cache is nil
+func SetGitCache(cache *GitCache) {
+	gitCache = cache
+	fetchedRepos = map[string]bool{}
+}
+
+// DefaultGitCacheDir gets the path to the folder, under the users cache folder, where
+// remote Git repositories are cached by default
+func DefaultGitCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(cacheDir, "rejigger", "git"), nil
+}
+
+// CleanGitCache removes every repository stored in a Git cache folder
+func CleanGitCache(dir string) error {
+	return errors.WithStack(os.RemoveAll(dir))
+}
+
+// repoDir gets the path to the folder a remote Git repository is cached in. Each
+// repository is stored in a folder named after a hash of its URL
+func (c GitCache) repoDir(gitURL string) string {
+	hash := sha256.Sum256([]byte(gitURL))
+	return filepath.Join(c.Dir, hex.EncodeToString(hash[:]))
+}
+
+// openRepository opens the cached copy of a remote Git repository. Repositories which
+// haven't been cached yet are cloned into the cache, and repositories which have are
+// updated with any changes made to the remote repository since they were last used
+func (c GitCache) openRepository(gitURL string) (*git.Repository, error) {
+	repoDir := c.repoDir(gitURL)
+	repo, err := git.PlainOpen(repoDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if c.Offline {
+			return nil, e.NewSimpleError(fmt.Sprintf("Git repository %s can not be loaded while offline because it has not been cached yet", gitURL))
+		}
+		return c.cloneRepository(gitURL, repoDir)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open cached copy of Git repository: "+gitURL)
+	}
+	if c.Offline || fetchedRepos[repoDir] {
+		return repo, nil
+	}
+
+	auth, err := getGitAuth(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.Fetch(&git.FetchOptions{Auth: auth, Tags: git.AllTags, Force: true})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, errors.Wrap(err, "Failed to update cached copy of Git repository: "+gitURL)
+	}
+	if err = syncBranches(repo); err != nil {
+		return nil, err
+	}
+	fetchedRepos[repoDir] = true
+	return repo, nil
+}
+
+// cloneRepository clones a remote Git repository into the cache
+func (c GitCache) cloneRepository(gitURL string, repoDir string) (*git.Repository, error) {
+	auth, err := getGitAuth(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.PlainClone(repoDir, true, &git.CloneOptions{URL: gitURL, Auth: auth, Tags: git.AllTags})
+	if err != nil {
+		// Make sure partial clones don't get mistaken for cached repositories later on
+		if rmErr := os.RemoveAll(repoDir); rmErr != nil {
+			return nil, errors.WithStack(rmErr)
+		}
+		return nil, errors.Wrap(err, "Failed to load remote Git repository: "+gitURL)
+	}
+	fetchedRepos[repoDir] = true
+	return repo, nil
+}
+
+// syncBranches updates the local branches in a cached repository to point to the same
+// commits as the branches in the remote repository. Fetching only updates the remote
+// tracking branches, and branches are resolved by their local name first
+func syncBranches(repo *git.Repository) error {
+	refs, err := repo.References()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	prefix := "refs/remotes/" + git.DefaultRemoteName + "/"
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		if ref.Type() != plumbing.HashReference || !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		branch := plumbing.NewBranchReferenceName(strings.TrimPrefix(name, prefix))
+		return repo.Storer.SetReference(plumbing.NewHashReference(branch, ref.Hash()))
+	})
+	return errors.WithStack(err)
+}
+
+// checkout loads a specific revision of a remote Git repository from the cache into an
+// in-memory virtual file system, returning the file system along with the hash of the
+// commit that was checked out
+func (c GitCache) checkout(gitURL string, revision string) (afero.Fs, string, error) {
+	appFS := afero.NewMemMapFs()
+	repo, err := c.openRepository(gitURL)
+	if err != nil {
+		return appFS, "", err
+	}
+	hash, err := resolveRevision(repo, gitURL, revision)
+	if err != nil {
+		return appFS, "", err
+	}
+
+	// The files are copied out of the cached repository rather than checked out through
+	// a worktree, so the state of the cached repository is never modified
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return appFS, "", errors.WithStack(err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return appFS, "", errors.WithStack(err)
+	}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		return writeGitFile(appFS, file)
+	})
+	if err != nil {
+		return appFS, "", errors.Wrap(err, fmt.Sprintf("Failed to check out revision %s of Git repository: %s", revision, gitURL))
+	}
+	return appFS, hash.String(), nil
+}
+
+// writeGitFile writes a file from a Git repository to a virtual file system
+func writeGitFile(appFS afero.Fs, file *object.File) error {
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = appFS.MkdirAll(path.Dir(file.Name), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if mode&os.ModeSymlink != 0 {
+		// The contents of a symbolic link is the path to its target
+		linker, ok := appFS.(afero.Linker)
+		if !ok {
+			return &os.LinkError{Op: "symlink", Old: contents, New: file.Name, Err: afero.ErrNoSymlink}
+		}
+		return errors.WithStack(linker.SymlinkIfPossible(contents, file.Name))
+	}
+	return errors.WithStack(afero.WriteFile(appFS, file.Name, []byte(contents), mode.Perm()))
+}
+
+// cachedTags gets the names of all tags defined in the cached copy of a remote Git repository
+func (c GitCache) cachedTags(gitURL string) ([]string, error) {
+	repo, err := c.openRepository(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var retval []string
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		retval = append(retval, ref.Name().Short())
+		return nil
+	})
+	return retval, errors.WithStack(err)
+}
@@ -0,0 +1,88 @@
+package lib
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/lib/internal"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+func Test_GitCache(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a local Git repository with a single commit
+	repoDir := t.TempDir()
+	repo, worktree, commit := internal.NewGitRepo(r, repoDir, "file.txt")
+	first := commit("Hello")
+	gitURL := "file://" + repoDir
+
+	// and a Git cache
+	cacheDir := t.TempDir()
+	SetGitCache(&GitCache{Dir: cacheDir})
+	defer SetGitCache(nil)
+
+	// When we load the repository
+	fs, hash, err := CloneGitRepository(gitURL, "")
+	r.NoError(err)
+
+	// Then the repository should be checked out and stored in the cache
+	a.Equal(first.String(), hash)
+	contents, err := afero.ReadFile(fs, "file.txt")
+	r.NoError(err)
+	a.Equal("Hello", string(contents))
+	entries, err := os.ReadDir(cacheDir)
+	r.NoError(err)
+	a.Len(entries, 1)
+
+	// When changes are made to the repository and it is loaded again in a later run
+	second := commit("World")
+	_, err = repo.CreateTag("v1.0", second, nil)
+	r.NoError(err)
+	r.NoError(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	feature := commit("Feature")
+	r.NoError(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+	SetGitCache(&GitCache{Dir: cacheDir})
+
+	// Then the changes should be fetched into the cache
+	fs, hash, err = CloneGitRepository(gitURL, "master")
+	r.NoError(err)
+	a.Equal(second.String(), hash)
+	contents, err = afero.ReadFile(fs, "file.txt")
+	r.NoError(err)
+	a.Equal("World", string(contents))
+	_, hash, err = CloneGitRepository(gitURL, "feature")
+	r.NoError(err)
+	a.Equal(feature.String(), hash)
+	tags, err := ListGitTags(gitURL)
+	r.NoError(err)
+	a.Equal([]string{"v1.0"}, tags)
+
+	// When the remote repository can no longer be accessed and we load it while offline
+	r.NoError(os.RemoveAll(repoDir))
+	SetGitCache(&GitCache{Dir: cacheDir, Offline: true})
+
+	// Then the cached copy of the repository should be used
+	fs, hash, err = CloneGitRepository(gitURL, "")
+	r.NoError(err)
+	a.Equal(second.String(), hash)
+	contents, err = afero.ReadFile(fs, "file.txt")
+	r.NoError(err)
+	a.Equal("World", string(contents))
+
+	// and repositories which haven't been cached should fail to load
+	_, _, err = CloneGitRepository("file://"+t.TempDir(), "")
+	r.Error(err)
+	a.Contains(err.Error(), "offline")
+
+	// When we clean the cache
+	r.NoError(CleanGitCache(cacheDir))
+
+	// Then no cached repositories should remain
+	a.NoDirExists(cacheDir)
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// Signature gets the author details used for commits and annotated tags made by tests
+func Signature() *object.Signature {
+	return &object.Signature{Name: "Tester", Email: "tester@example.com", When: time.Now()}
+}
+
+// NewGitRepo creates a Git repository in repoDir, returning the repository, its worktree
+// and a function which writes new contents to fileName and commits it to the current branch
+func NewGitRepo(r *require.Assertions, repoDir string, fileName string) (*git.Repository, *git.Worktree, func(contents string) plumbing.Hash) {
+	repo, err := git.PlainInit(repoDir, false)
+	r.NoError(err)
+	worktree, err := repo.Worktree()
+	r.NoError(err)
+	commit := func(contents string) plumbing.Hash {
+		r.NoError(os.WriteFile(filepath.Join(repoDir, fileName), []byte(contents), 0600))
+		_, err := worktree.Add(fileName)
+		r.NoError(err)
+		hash, err := worktree.Commit("Update "+fileName, &git.CommitOptions{Author: Signature()})
+		r.NoError(err)
+		return hash
+	}
+	return repo, worktree, commit
+}
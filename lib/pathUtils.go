@@ -3,8 +3,16 @@ package lib
 import (
 	"log"
 	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
+// GlobExclusionPrefix prefix used to identify exclusion patterns which use gitignore-style
+// glob syntax rather than regular expressions
+const GlobExclusionPrefix = "glob:"
+
 // DirExists checks to see if the Path given points to a folder that exists
 func DirExists(path string) bool {
 	info, err := os.Stat(path)
@@ -18,3 +26,108 @@ func DirExists(path string) bool {
 	return info.IsDir()
 
 }
+
+// CompileGlob converts a glob pattern into an equivalent regular expression which
+// matches slash separated paths. The following wildcards are supported:
+//
+//   - "*" matches any sequence of characters except path separators
+//   - "**" matches any sequence of characters including path separators
+//   - "?" matches any single character except path separators
+//   - "[abc]" matches any one of the characters in the brackets ("[!abc]" negates the set)
+//
+// Patterns ending in "/**" also match the parent folder itself, so a pattern like
+// "docs/**" will match the "docs" folder as well as all of its contents
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				switch {
+				case i+1 < len(pattern) && pattern[i+1] == '/':
+					// "**/" matches 0 or more complete folder names
+					i++
+					expr.WriteString("(.*/)?")
+				case i > 1 && pattern[i-2] == '/' && i+1 == len(pattern):
+					// trailing "/**" matches the parent folder and all of its contents
+					// so we need to make the preceding slash optional as well
+					str := expr.String()
+					expr.Reset()
+					expr.WriteString(str[:len(str)-1])
+					expr.WriteString("(/.*)?")
+				default:
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, errors.New("Unterminated character class in glob pattern: " + pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	retval, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid glob pattern: "+pattern)
+	}
+	return retval, nil
+}
+
+// MatchGlob returns true if the given slash separated path matches the glob pattern
+// See CompileGlob for details on the supported syntax
+func MatchGlob(pattern string, path string) (bool, error) {
+	expr, err := CompileGlob(pattern)
+	if err != nil {
+		return false, err
+	}
+	return expr.MatchString(path), nil
+}
+
+// CompileExclusion converts a file exclusion pattern into a regular expression which matches
+// slash separated paths relative to the root of a template. Patterns are assumed to be
+// regular expressions unless they start with the GlobExclusionPrefix, in which case they are
+// interpreted as gitignore-style globs:
+//
+//   - patterns starting with a slash are matched relative to the template root
+//   - patterns containing a slash anywhere other than at the end are matched relative to the
+//     template root
+//   - all other patterns are matched against files and folders at any level of the template
+//   - matching a folder also matches all of its contents
+func CompileExclusion(pattern string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(pattern, GlobExclusionPrefix) {
+		retval, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return retval, nil
+	}
+
+	glob := strings.TrimSpace(strings.TrimPrefix(pattern, GlobExclusionPrefix))
+	glob = strings.TrimSuffix(glob, "/")
+	if strings.HasPrefix(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+	} else if !strings.Contains(glob, "/") {
+		glob = "**/" + glob
+	}
+	if !strings.HasSuffix(glob, "/**") {
+		glob += "/**"
+	}
+	return CompileGlob(glob)
+}
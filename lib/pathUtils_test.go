@@ -51,3 +51,167 @@ func Test_InvalidDirExists(t *testing.T) {
 	result := DirExists(".\x00asdf")
 	require.False(t, result)
 }
+
+func Test_MatchGlob(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		"Exact match": {
+			pattern:  "README.md",
+			path:     "README.md",
+			expected: true,
+		},
+		"Single star": {
+			pattern:  "*.md",
+			path:     "README.md",
+			expected: true,
+		},
+		"Single star does not cross folders": {
+			pattern:  "*.md",
+			path:     "docs/README.md",
+			expected: false,
+		},
+		"Double star crosses folders": {
+			pattern:  "**/*.md",
+			path:     "docs/api/README.md",
+			expected: true,
+		},
+		"Leading double star matches root": {
+			pattern:  "**/*.md",
+			path:     "README.md",
+			expected: true,
+		},
+		"Trailing double star matches contents": {
+			pattern:  ".github/**",
+			path:     ".github/workflows/build.yml",
+			expected: true,
+		},
+		"Trailing double star matches folder": {
+			pattern:  ".github/**",
+			path:     ".github",
+			expected: true,
+		},
+		"Trailing double star does not match prefix": {
+			pattern:  ".github/**",
+			path:     ".githubx",
+			expected: false,
+		},
+		"Middle double star": {
+			pattern:  "src/**/test.go",
+			path:     "src/test.go",
+			expected: true,
+		},
+		"Question mark": {
+			pattern:  "file?.txt",
+			path:     "file1.txt",
+			expected: true,
+		},
+		"Character class": {
+			pattern:  "file[12].txt",
+			path:     "file3.txt",
+			expected: false,
+		},
+		"Negated character class": {
+			pattern:  "file[!12].txt",
+			path:     "file3.txt",
+			expected: true,
+		},
+		"Regex characters are escaped": {
+			pattern:  "a+b.txt",
+			path:     "aab.txt",
+			expected: false,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := MatchGlob(data.pattern, data.path)
+			r.NoError(err)
+			r.Equal(data.expected, result)
+		})
+	}
+}
+
+func Test_MatchGlobInvalid(t *testing.T) {
+	r := require.New(t)
+
+	_, err := MatchGlob("file[12.txt", "file1.txt")
+	r.Error(err)
+}
+
+func Test_CompileExclusion(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		"Regex": {
+			pattern:  "docs/.*",
+			path:     "docs/index.md",
+			expected: true,
+		},
+		"Regex no match": {
+			pattern:  "docs/.*",
+			path:     "src/main.go",
+			expected: false,
+		},
+		"Glob file name matches at any level": {
+			pattern:  "glob:*.log",
+			path:     "logs/debug.log",
+			expected: true,
+		},
+		"Glob file name matches at root": {
+			pattern:  "glob:*.log",
+			path:     "debug.log",
+			expected: true,
+		},
+		"Glob folder matches contents": {
+			pattern:  "glob:node_modules/",
+			path:     "web/node_modules/lib/index.js",
+			expected: true,
+		},
+		"Glob anchored to root": {
+			pattern:  "glob:/build",
+			path:     "src/build",
+			expected: false,
+		},
+		"Glob anchored to root matches": {
+			pattern:  "glob:/build",
+			path:     "build/output.bin",
+			expected: true,
+		},
+		"Glob with nested path is anchored": {
+			pattern:  "glob:docs/*.md",
+			path:     "src/docs/index.md",
+			expected: false,
+		},
+		"Glob with nested path matches": {
+			pattern:  "glob:docs/*.md",
+			path:     "docs/index.md",
+			expected: true,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, err := CompileExclusion(data.pattern)
+			r.NoError(err)
+			r.Equal(data.expected, expr.MatchString(data.path))
+		})
+	}
+}
+
+func Test_CompileExclusionInvalid(t *testing.T) {
+	r := require.New(t)
+
+	_, err := CompileExclusion("**/notvalid/*?.")
+	r.Error(err)
+	_, err = CompileExclusion("glob:[notvalid")
+	r.Error(err)
+}
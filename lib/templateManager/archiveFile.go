@@ -0,0 +1,136 @@
+package templateManager
+
+import (
+	"path/filepath"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// ArchiveFileName name of the file generated in the root folder of every project, which
+// records how the project was generated
+const ArchiveFileName = ".rejig.archive.yml"
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//									DATA STRUCTURES
+
+// ArchiveTemplateData metadata describing the template a project was generated from
+type ArchiveTemplateData struct {
+	// Name friendly name of the template used to generate the project
+	Name string `yaml:"name"`
+	// Type identifier describing the protocol used to retrieve the template content
+	Type ao.TemplateSourceType `yaml:"type"`
+	// Source path or URL where the template was found
+	Source string `yaml:"source"`
+	// SubDir optional sub-directory under the Source location where the template was found
+	SubDir string `yaml:"subdir,omitempty"`
+	// Ref branch name, tag name or commit hash the template was pinned to, if any. Projects
+	// are updated using the latest revision of the template matching this ref
+	Ref string `yaml:"ref,omitempty"`
+	// Constraint version constraint used to select the release of the template, if any.
+	// Projects are updated using the highest release satisfying this constraint
+	Constraint string `yaml:"constraint,omitempty"`
+	// Tag name of the tag identifying the release of the template selected using the
+	// version constraint
+	Tag string `yaml:"tag,omitempty"`
+	// Revision identifier for the revision of the template that was used, such as the
+	// Git commit hash. Empty for template sources which don't support revisions
+	Revision string `yaml:"revision,omitempty"`
+	// Version the version number of the template, as defined in the template manifest
+	Version string `yaml:"version,omitempty"`
+}
+
+// ArchiveData parsed content of the archive file generated alongside a project, which
+// records all the information needed to reproduce or update the project later
+type ArchiveData struct {
+	// Template metadata describing the template the project was generated from
+	Template ArchiveTemplateData `yaml:"template"`
+	// Rejigger version of the Rejigger application used to generate the project
+	Rejigger string `yaml:"rejigger"`
+	// Args values for all template args used to generate the project, in the same character
+	// string form accepted when they were provided by the user
+	Args map[string]string `yaml:"args"`
+}
+
+// GetTemplateOptions gets the options needed to load the template the project was
+// generated from
+func (a *ArchiveData) GetTemplateOptions() ao.TemplateOptions {
+	return ao.TemplateOptions{
+		Type:    a.Template.Type,
+		Source:  a.Template.Source,
+		Name:    a.Template.Name,
+		SubDir:  a.Template.SubDir,
+		Ref:     a.Template.Ref,
+		Version: a.Template.Constraint,
+	}
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//									PARSING LOGIC
+
+// ParseArchive parses the archive file from the root folder of a generated project
+func ParseArchive(srcFS afero.Fs, projectPath string) (ArchiveData, error) {
+	var retval ArchiveData
+	archivePath := filepath.Join(projectPath, ArchiveFileName)
+	buf, err := afero.ReadFile(srcFS, archivePath)
+	if err != nil {
+		return retval, errors.WithStack(err)
+	}
+
+	err = yaml.Unmarshal(buf, &retval)
+	if err != nil {
+		return retval, errors.Wrap(err, "Error parsing project archive: "+archivePath)
+	}
+	return retval, nil
+}
+
+// getArchiveData generates a summary of the template and user defined options used
+// to generate a project
+func (t *templateManager) getArchiveData() ArchiveData {
+	source := t.Options.GetSource()
+	if t.Options.GetType() == ao.TstLocal {
+		// Relative paths are resolved so the template can be found from any location
+		if absSource, err := filepath.Abs(source); err == nil {
+			source = absSource
+		}
+	}
+	retval := ArchiveData{
+		Template: ArchiveTemplateData{
+			Name:       t.Options.GetName(),
+			Type:       t.Options.GetType(),
+			Source:     source,
+			SubDir:     t.Options.SubDir,
+			Ref:        t.Options.Ref,
+			Constraint: t.Options.Version,
+			Tag:        t.srcTag,
+			Revision:   t.srcRevision,
+			Version:    t.manifestData.Versions.Template.Original(),
+		},
+		Rejigger: lib.Version,
+		Args:     map[string]string{},
+	}
+	for _, curArg := range t.manifestData.Template.Args {
+		if value, ok := t.templateContext[curArg.Name]; ok {
+			retval.Args[curArg.Name] = formatArgValue(value)
+		}
+	}
+	return retval
+}
+
+// WriteArchive generates an archive file in the root folder of a generated project,
+// recording the template and user defined options used to generate it
+func (t *templateManager) WriteArchive(targetFS afero.Fs, targetPath string) error {
+	data, err := yaml.Marshal(t.getArchiveData())
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode project archive")
+	}
+	archivePath := filepath.Join(targetPath, ArchiveFileName)
+	err = afero.WriteFile(targetFS, archivePath, data, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Failed to generate project archive "+archivePath)
+	}
+	return nil
+}
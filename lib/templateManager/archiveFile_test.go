@@ -0,0 +1,66 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteArchive(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with user provided values for its args
+	options := ao.TemplateOptions{
+		Source: getProjectDir(),
+		Name:   "MyTemplate",
+		Type:   ao.TstLocal,
+	}
+	tm, err := New(options)
+	r.NoError(err)
+	r.NoError(tm.SetParams(map[string]string{"project_name": "MyProj", "version": "1.2.3"}))
+
+	// When we generate an archive file for the project
+	targetFS := afero.NewMemMapFs()
+	r.NoError(tm.WriteArchive(targetFS, "/proj"))
+
+	// Then the archive should describe how the project was generated
+	result, err := ParseArchive(targetFS, "/proj")
+	r.NoError(err)
+	a.Equal("MyTemplate", result.Template.Name)
+	a.Equal(ao.TstLocal, result.Template.Type)
+	a.Equal(getProjectDir(), result.Template.Source)
+	a.Empty(result.Template.Revision)
+	a.Equal("1.0", result.Template.Version)
+	a.Equal(lib.Version, result.Rejigger)
+	a.Equal(map[string]string{"project_name": "MyProj", "version": "1.2.3"}, result.Args)
+
+	// and it should be possible to load the template from the archive
+	a.Equal(options, result.GetTemplateOptions())
+}
+
+func Test_ParseArchiveInvalid(t *testing.T) {
+	r := require.New(t)
+
+	// Given an archive file with invalid content
+	srcFS := afero.NewMemMapFs()
+	r.NoError(afero.WriteFile(srcFS, "/proj/"+ArchiveFileName, []byte("template: [fubar"), 0644))
+
+	// When we parse the archive
+	_, err := ParseArchive(srcFS, "/proj")
+
+	// Then an error should be returned
+	r.Error(err)
+	r.Contains(err.Error(), ArchiveFileName)
+}
+
+func Test_ParseArchiveMissing(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ParseArchive(afero.NewMemMapFs(), "/proj")
+	r.Error(err)
+}
@@ -0,0 +1,216 @@
+package templateManager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//																				    ArgType
+
+// ArgType enum for all data types supported by template arguments
+type ArgType int64
+
+const (
+	// AtUndefined No data type defined for the argument. Treated as a string.
+	AtUndefined ArgType = iota
+	// AtUnknown Data type provided but not currently supported
+	AtUnknown
+	// AtString Argument is a free-form character string
+	AtString
+	// AtInt Argument is a whole number
+	AtInt
+	// AtFloat Argument is a decimal number
+	AtFloat
+	// AtBool Argument is a true / false flag
+	AtBool
+	// AtSemver Argument is a semantic version number
+	AtSemver
+	// AtList Argument is a comma separated list of character strings
+	AtList
+	// AtEnum Argument must be one of a fixed set of character strings
+	AtEnum
+)
+
+// toString Converts the value from our enumeration to a string representation
+func (a *ArgType) toString() string {
+	switch *a {
+	case AtString:
+		return "string"
+	case AtInt:
+		return "int"
+	case AtFloat:
+		return "float"
+	case AtBool:
+		return "bool"
+	case AtSemver:
+		return "semver"
+	case AtList:
+		return "list"
+	case AtEnum:
+		return "enum"
+	case AtUndefined:
+		fallthrough
+	case AtUnknown:
+		fallthrough
+	default:
+		return ""
+	}
+}
+
+// fromString populates our enumeration from an arbitrary character string
+func (a *ArgType) fromString(value string) {
+	switch value {
+	case "string":
+		*a = AtString
+	case "int":
+		*a = AtInt
+	case "float":
+		*a = AtFloat
+	case "bool":
+		*a = AtBool
+	case "semver":
+		*a = AtSemver
+	case "list":
+		*a = AtList
+	case "enum":
+		*a = AtEnum
+	case "":
+		*a = AtUndefined
+	default:
+		*a = AtUnknown
+	}
+}
+
+// UnmarshalYAML decodes values for our enumeration from YAML content
+func (a *ArgType) UnmarshalYAML(value *yaml.Node) error {
+	var temp string
+	if err := value.Decode(&temp); err != nil {
+		return errors.Wrap(err, "Unable to parse argument type: "+value.Value)
+	}
+	a.fromString(temp)
+	return nil
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//																			Value Conversion
+
+// semverValue native value stored for semantic version arguments. It behaves like the
+// parsed version, but is rendered exactly as the user entered it so "1.0" isn't expanded
+// to "1.0.0" in generated files
+type semverValue struct {
+	*version.Version
+}
+
+// String gets the original text the version was parsed from
+func (v semverValue) String() string {
+	return v.Original()
+}
+
+// parseArgValue converts a raw character string provided by the user into the native
+// data type associated with the given argument. An error is returned if the raw value
+// is not compatible with the argument's data type, or if the argument defines a set
+// of choices and the raw value is not one of them
+func parseArgValue(arg ArgData, raw string) (any, error) {
+	if len(arg.Choices) != 0 && !arg.isChoice(raw) {
+		return nil, newArgValueError(arg, raw, "expected one of "+strings.Join(arg.Choices, ", "))
+	}
+
+	switch arg.Type {
+	case AtInt:
+		retval, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, newArgValueError(arg, raw, "expected a whole number")
+		}
+		return retval, nil
+	case AtFloat:
+		retval, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, newArgValueError(arg, raw, "expected a decimal number")
+		}
+		return retval, nil
+	case AtBool:
+		switch strings.ToLower(raw) {
+		case "true", "yes", "y", "1":
+			return true, nil
+		case "false", "no", "n", "0":
+			return false, nil
+		default:
+			return nil, newArgValueError(arg, raw, "expected one of true/false/yes/no")
+		}
+	case AtSemver:
+		retval, err := version.NewSemver(raw)
+		if err != nil {
+			return nil, newArgValueError(arg, raw, "expected a semantic version number")
+		}
+		return semverValue{retval}, nil
+	case AtList:
+		retval := []string{}
+		for _, curItem := range strings.Split(raw, ",") {
+			curItem = strings.TrimSpace(curItem)
+			if curItem != "" {
+				retval = append(retval, curItem)
+			}
+		}
+		return retval, nil
+	case AtEnum:
+		fallthrough
+	case AtString:
+		fallthrough
+	case AtUndefined:
+		return raw, nil
+	case AtUnknown:
+		fallthrough
+	default:
+		panic("Unsupported argument type " + arg.Type.toString())
+	}
+}
+
+// formatArgValue converts a native value stored in the template context back into the
+// character string representation it was parsed from, such that passing the result to
+// parseArgValue produces an equivalent value
+func formatArgValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case semverValue:
+		return v.Original()
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// newArgValueError generates a descriptive error explaining why a value provided for
+// a template argument was rejected
+func newArgValueError(arg ArgData, raw string, reason string) error {
+	return e.NewSimpleError(fmt.Sprintf("Invalid value '%s' for %s: %s", raw, arg.Name, reason))
+}
+
+// resolveChoice maps a selection made from a numbered list of choices back to the
+// value of the selected choice. Values which are not valid selection numbers are
+// returned unmodified. Literal choices take precedence over selection numbers so
+// choices which are themselves numbers can still be selected by value
+func resolveChoice(arg ArgData, raw string) string {
+	if len(arg.Choices) == 0 || arg.isChoice(raw) {
+		return raw
+	}
+	index, err := strconv.Atoi(raw)
+	if err != nil || index < 1 || index > len(arg.Choices) {
+		return raw
+	}
+	return arg.Choices[index-1]
+}
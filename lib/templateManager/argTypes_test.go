@@ -0,0 +1,314 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ArgTypeStringConversion(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		source string
+		target ArgType
+	}{
+		"String": {
+			source: "string",
+			target: AtString,
+		},
+		"Int": {
+			source: "int",
+			target: AtInt,
+		},
+		"Float": {
+			source: "float",
+			target: AtFloat,
+		},
+		"Bool": {
+			source: "bool",
+			target: AtBool,
+		},
+		"Semver": {
+			source: "semver",
+			target: AtSemver,
+		},
+		"List": {
+			source: "list",
+			target: AtList,
+		},
+		"Enum": {
+			source: "enum",
+			target: AtEnum,
+		},
+		"Empty string": {
+			source: "",
+			target: AtUndefined,
+		},
+		"Unknown": {
+			source: "fubar",
+			target: AtUnknown,
+		},
+	}
+	r.Equal(int(AtEnum)+1, len(tests))
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var temp ArgType
+			temp.fromString(data.source)
+			r.Equal(data.target, temp)
+			if data.target != AtUnknown {
+				r.Equal(data.source, temp.toString())
+			}
+		})
+	}
+}
+
+func Test_parseArgValue(t *testing.T) {
+	r := require.New(t)
+
+	expVersion, err := version.NewSemver("1.2.3")
+	r.NoError(err)
+
+	tests := map[string]struct {
+		arg      ArgData
+		raw      string
+		expected any
+	}{
+		"Undefined type": {
+			arg:      ArgData{Name: "arg"},
+			raw:      "Hello World",
+			expected: "Hello World",
+		},
+		"String": {
+			arg:      ArgData{Name: "arg", Type: AtString},
+			raw:      "Hello World",
+			expected: "Hello World",
+		},
+		"Int": {
+			arg:      ArgData{Name: "arg", Type: AtInt},
+			raw:      "42",
+			expected: 42,
+		},
+		"Float": {
+			arg:      ArgData{Name: "arg", Type: AtFloat},
+			raw:      "3.5",
+			expected: 3.5,
+		},
+		"Bool true": {
+			arg:      ArgData{Name: "arg", Type: AtBool},
+			raw:      "Yes",
+			expected: true,
+		},
+		"Bool false": {
+			arg:      ArgData{Name: "arg", Type: AtBool},
+			raw:      "false",
+			expected: false,
+		},
+		"Semver": {
+			arg:      ArgData{Name: "arg", Type: AtSemver},
+			raw:      "1.2.3",
+			expected: semverValue{expVersion},
+		},
+		"List": {
+			arg:      ArgData{Name: "arg", Type: AtList},
+			raw:      "one, two,,three ",
+			expected: []string{"one", "two", "three"},
+		},
+		"Empty list": {
+			arg:      ArgData{Name: "arg", Type: AtList},
+			raw:      "",
+			expected: []string{},
+		},
+		"Enum": {
+			arg:      ArgData{Name: "arg", Type: AtEnum, Choices: []string{"MIT", "GPL"}},
+			raw:      "GPL",
+			expected: "GPL",
+		},
+		"Semver with choices": {
+			arg:      ArgData{Name: "arg", Type: AtSemver, Choices: []string{"1.2.3", "2.0.0"}},
+			raw:      "1.2.3",
+			expected: semverValue{expVersion},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := parseArgValue(data.arg, data.raw)
+			r.NoError(err)
+			r.Equal(data.expected, result)
+		})
+	}
+}
+
+func Test_parseArgValueSemverRendering(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		raw      string
+		expected string
+	}{
+		"Short version": {
+			raw:      "1.0",
+			expected: "1.0 1 0",
+		},
+		"Prefixed version": {
+			raw:      "v2.3.1-rc1",
+			expected: "v2.3.1-rc1 2 3",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a semantic version argument
+			value, err := parseArgValue(ArgData{Name: "arg", Type: AtSemver}, data.raw)
+			r.NoError(err)
+
+			// When we render it in a template
+			tpl, err := pongo2.FromString("{{ ver }} {{ ver.Segments.0 }} {{ ver.Segments.1 }}")
+			r.NoError(err)
+			output, err := tpl.Execute(pongo2.Context{"ver": value})
+
+			// Then the version should be rendered as it was entered
+			r.NoError(err)
+			r.Equal(data.expected, output)
+		})
+	}
+}
+
+func Test_parseArgValueInvalid(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		arg ArgData
+		raw string
+	}{
+		"Int": {
+			arg: ArgData{Name: "arg", Type: AtInt},
+			raw: "forty two",
+		},
+		"Float": {
+			arg: ArgData{Name: "arg", Type: AtFloat},
+			raw: "1.2.3",
+		},
+		"Bool": {
+			arg: ArgData{Name: "arg", Type: AtBool},
+			raw: "maybe",
+		},
+		"Semver": {
+			arg: ArgData{Name: "arg", Type: AtSemver},
+			raw: "latest",
+		},
+		"Enum": {
+			arg: ArgData{Name: "arg", Type: AtEnum, Choices: []string{"MIT", "GPL"}},
+			raw: "BSD",
+		},
+		"String with choices": {
+			arg: ArgData{Name: "arg", Choices: []string{"github", "gitlab"}},
+			raw: "jenkins",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseArgValue(data.arg, data.raw)
+			r.Error(err)
+			r.Contains(err.Error(), data.raw)
+			r.Contains(err.Error(), data.arg.Name)
+		})
+	}
+}
+
+func Test_parseArgValueUnknownType(t *testing.T) {
+	r := require.New(t)
+
+	r.Panics(func() {
+		_, _ = parseArgValue(ArgData{Name: "arg", Type: AtUnknown}, "value")
+	})
+}
+
+func Test_resolveChoice(t *testing.T) {
+	r := require.New(t)
+
+	choices := []string{"MIT", "GPL", "3"}
+	tests := map[string]struct {
+		arg      ArgData
+		raw      string
+		expected string
+	}{
+		"Selection number": {
+			arg:      ArgData{Name: "arg", Choices: choices},
+			raw:      "2",
+			expected: "GPL",
+		},
+		"Literal value": {
+			arg:      ArgData{Name: "arg", Choices: choices},
+			raw:      "MIT",
+			expected: "MIT",
+		},
+		"Literal value takes precedence": {
+			arg:      ArgData{Name: "arg", Choices: choices},
+			raw:      "3",
+			expected: "3",
+		},
+		"Selection out of range": {
+			arg:      ArgData{Name: "arg", Choices: choices},
+			raw:      "4",
+			expected: "4",
+		},
+		"No choices": {
+			arg:      ArgData{Name: "arg"},
+			raw:      "1",
+			expected: "1",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			r.Equal(data.expected, resolveChoice(data.arg, data.raw))
+		})
+	}
+}
+
+func Test_formatArgValue(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		arg ArgData
+		raw string
+	}{
+		"String": {
+			arg: ArgData{Name: "arg", Type: AtString},
+			raw: "Hello World",
+		},
+		"Int": {
+			arg: ArgData{Name: "arg", Type: AtInt},
+			raw: "42",
+		},
+		"Float": {
+			arg: ArgData{Name: "arg", Type: AtFloat},
+			raw: "3.5",
+		},
+		"Bool": {
+			arg: ArgData{Name: "arg", Type: AtBool},
+			raw: "true",
+		},
+		"Semver": {
+			arg: ArgData{Name: "arg", Type: AtSemver},
+			raw: "v1.2.3-rc1",
+		},
+		"List": {
+			arg: ArgData{Name: "arg", Type: AtList},
+			raw: "one,two,three",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, err := parseArgValue(data.arg, data.raw)
+			r.NoError(err)
+			r.Equal(data.raw, formatArgValue(value))
+		})
+	}
+}
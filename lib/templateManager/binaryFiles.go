@@ -0,0 +1,83 @@
+package templateManager
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// sniffLength number of bytes read from the start of a file when trying to determine
+// whether it contains binary content
+const sniffLength = 8000
+
+// binaryExtensions file extensions which are always assumed to contain binary content
+var binaryExtensions = map[string]bool{
+	// Images
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true, ".ico": true,
+	".webp": true, ".tif": true, ".tiff": true, ".psd": true,
+	// Fonts
+	".ttf": true, ".otf": true, ".woff": true, ".woff2": true, ".eot": true,
+	// Archives
+	".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".7z": true,
+	".rar": true, ".tar": true,
+	// Compiled artifacts
+	".jar": true, ".war": true, ".class": true, ".exe": true, ".dll": true, ".so": true,
+	".dylib": true, ".o": true, ".a": true, ".lib": true, ".pyc": true, ".wasm": true,
+	// Documents and media
+	".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true, ".ppt": true,
+	".pptx": true, ".mp3": true, ".mp4": true, ".wav": true, ".ogg": true, ".mov": true,
+	".avi": true,
+	// Databases
+	".db": true, ".sqlite": true, ".sqlite3": true,
+}
+
+// isBinaryFile returns true if the given file appears to contain binary content, based on
+// its file extension or on the first few bytes of its contents
+func isBinaryFile(srcFS afero.Fs, path string) (bool, error) {
+	if binaryExtensions[strings.ToLower(filepath.Ext(path))] {
+		return true, nil
+	}
+
+	fh, err := srcFS.Open(path)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	buf := make([]byte, sniffLength)
+	count, err := io.ReadFull(fh, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		lib.SNF(fh.Close())
+		return false, errors.WithStack(err)
+	}
+	if err = fh.Close(); err != nil {
+		return false, errors.WithStack(err)
+	}
+	return isBinaryContent(buf[:count], count == sniffLength), nil
+}
+
+// isBinaryContent returns true if the given data contains NUL bytes or is not valid
+// UTF-8 encoded text. If truncated is true, the data is assumed to be the first part
+// of a larger file, so a partial character at the end of the data is ignored
+func isBinaryContent(data []byte, truncated bool) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	if truncated {
+		// Drop any partial multi-byte character from the end of the buffer
+		for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+			if utf8.RuneStart(data[len(data)-i]) {
+				if !utf8.FullRune(data[len(data)-i:]) {
+					data = data[:len(data)-i]
+				}
+				break
+			}
+		}
+	}
+	return !utf8.Valid(data)
+}
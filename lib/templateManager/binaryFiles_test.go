@@ -0,0 +1,92 @@
+package templateManager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isBinaryContent(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		data      []byte
+		truncated bool
+		expected  bool
+	}{
+		"Plain text": {
+			data:     []byte("Hello {{ project_name }}"),
+			expected: false,
+		},
+		"Multi-byte text": {
+			data:     []byte("Héllo wörld"),
+			expected: false,
+		},
+		"Empty file": {
+			data:     []byte{},
+			expected: false,
+		},
+		"NUL bytes": {
+			data:     []byte("Hello\x00World"),
+			expected: true,
+		},
+		"Invalid UTF-8": {
+			data:     []byte{0xff, 0xfe, 'H', 'i'},
+			expected: true,
+		},
+		"Partial character at end of truncated data": {
+			data:      []byte("Hello \xc3"),
+			truncated: true,
+			expected:  false,
+		},
+		"Partial character at end of complete data": {
+			data:     []byte("Hello \xc3"),
+			expected: true,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			r.Equal(data.expected, isBinaryContent(data.data, data.truncated))
+		})
+	}
+}
+
+func Test_isBinaryFile(t *testing.T) {
+	r := require.New(t)
+
+	// Given a set of sample files
+	fs := afero.NewMemMapFs()
+	r.NoError(afero.WriteFile(fs, "logo.png", []byte("not really an image"), 0600))
+	r.NoError(afero.WriteFile(fs, "LOGO.JPG", []byte("not really an image"), 0600))
+	r.NoError(afero.WriteFile(fs, "README.md", []byte("{{ project_name }}"), 0600))
+	r.NoError(afero.WriteFile(fs, "fixture.dat", []byte{0x01, 0x00, 0x02}, 0600))
+	// Large text file with a multi-byte character spanning the sniff boundary
+	largeText := append(bytes.Repeat([]byte("a"), sniffLength-1), []byte("é")...)
+	r.NoError(afero.WriteFile(fs, "large.txt", largeText, 0600))
+
+	tests := map[string]bool{
+		"logo.png":    true,
+		"LOGO.JPG":    true,
+		"README.md":   false,
+		"fixture.dat": true,
+		"large.txt":   false,
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := isBinaryFile(fs, name)
+			r.NoError(err)
+			r.Equal(expected, result)
+		})
+	}
+}
+
+func Test_isBinaryFileNotExist(t *testing.T) {
+	r := require.New(t)
+
+	_, err := isBinaryFile(afero.NewMemMapFs(), "fubar.txt")
+	r.Error(err)
+}
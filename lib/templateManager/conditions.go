@@ -0,0 +1,38 @@
+package templateManager
+
+import (
+	"github.com/flosch/pongo2/v6"
+	"github.com/pkg/errors"
+)
+
+// conditionResult text produced by a condition template when the condition is met
+const conditionResult = "true"
+
+// compileCondition converts a template expression, like "use_docker" or
+// "ci != 'none'", into a template which produces the text "true" when the
+// expression evaluates to true
+func compileCondition(expr string) (*pongo2.Template, error) {
+	tpl, err := pongo2.FromString("{% if " + expr + " %}" + conditionResult + "{% endif %}")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse condition: "+expr)
+	}
+	return tpl, nil
+}
+
+// evaluateCondition applies a set of template args to a template expression and
+// returns true if the expression evaluates to true. Empty expressions always
+// evaluate to true
+func evaluateCondition(expr string, context map[string]any) (bool, error) {
+	if expr == "" {
+		return true, nil
+	}
+	tpl, err := compileCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	result, err := tpl.Execute(context)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to evaluate condition: "+expr)
+	}
+	return result == conditionResult, nil
+}
@@ -0,0 +1,62 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluateCondition(t *testing.T) {
+	r := require.New(t)
+
+	context := map[string]any{
+		"use_docker": true,
+		"ci":         "none",
+		"port":       8080,
+	}
+	tests := map[string]struct {
+		expr     string
+		expected bool
+	}{
+		"Empty expression": {
+			expr:     "",
+			expected: true,
+		},
+		"Boolean arg": {
+			expr:     "use_docker",
+			expected: true,
+		},
+		"Negated boolean arg": {
+			expr:     "not use_docker",
+			expected: false,
+		},
+		"String comparison": {
+			expr:     "ci != 'none'",
+			expected: false,
+		},
+		"Numeric comparison": {
+			expr:     "port > 1024",
+			expected: true,
+		},
+		"Undefined arg": {
+			expr:     "does_not_exist",
+			expected: false,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := evaluateCondition(data.expr, context)
+			r.NoError(err)
+			r.Equal(data.expected, result)
+		})
+	}
+}
+
+func Test_evaluateConditionInvalid(t *testing.T) {
+	r := require.New(t)
+
+	_, err := evaluateCondition("ci !=", map[string]any{})
+	r.Error(err)
+	r.Contains(err.Error(), "ci !=")
+}
@@ -0,0 +1,257 @@
+package templateManager
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//																		   ConflictStrategy
+
+// ConflictStrategy enum for all supported ways of handling generated files which
+// conflict with files that already exist in the target folder
+type ConflictStrategy int64
+
+const (
+	// CsUndefined No strategy defined. Treated the same as CsFail.
+	CsUndefined ConflictStrategy = iota
+	// CsUnknown Strategy provided but not currently supported
+	CsUnknown
+	// CsFail Nothing is generated if any generated file conflicts with an existing file
+	CsFail
+	// CsSkip Existing files are left untouched
+	CsSkip
+	// CsOverwrite Existing files are replaced with the generated files
+	CsOverwrite
+	// CsPrompt The user is asked how to handle each conflicting file
+	CsPrompt
+)
+
+// toString Converts the value from our enumeration to a string representation
+func (c *ConflictStrategy) toString() string {
+	switch *c {
+	case CsFail:
+		return "fail"
+	case CsSkip:
+		return "skip"
+	case CsOverwrite:
+		return "overwrite"
+	case CsPrompt:
+		return "prompt"
+	case CsUndefined:
+		fallthrough
+	case CsUnknown:
+		fallthrough
+	default:
+		return ""
+	}
+}
+
+// fromString populates our enumeration from an arbitrary character string
+func (c *ConflictStrategy) fromString(value string) {
+	switch value {
+	case "fail":
+		*c = CsFail
+	case "skip":
+		*c = CsSkip
+	case "overwrite":
+		*c = CsOverwrite
+	case "prompt":
+		*c = CsPrompt
+	case "":
+		*c = CsUndefined
+	default:
+		*c = CsUnknown
+	}
+}
+
+// String Converts the value from our enumeration to a string representation, for
+// use when displaying command line flags
+func (c *ConflictStrategy) String() string {
+	return c.toString()
+}
+
+// Set populates our enumeration from the value of a command line flag
+func (c *ConflictStrategy) Set(value string) error {
+	var temp ConflictStrategy
+	temp.fromString(value)
+	if temp == CsUnknown || temp == CsUndefined {
+		return e.NewSimpleError("Unsupported conflict strategy " + value + ", expected one of fail, skip, overwrite, prompt")
+	}
+	*c = temp
+	return nil
+}
+
+// Type describes the data type of our enumeration when displaying command line flags
+func (c *ConflictStrategy) Type() string {
+	return "strategy"
+}
+
+// -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
+//																			 Merging Output
+
+// MergeProject copies a generated project from the rootDir of srcFS to the same location
+// in targetFS. Generated files which are identical to existing files are ignored, and
+// those which differ from existing files are handled using the given conflict strategy
+func (t *templateManager) MergeProject(cmd *cobra.Command, srcFS afero.Fs, targetFS afero.Fs, rootDir string, strategy ConflictStrategy) error {
+	conflicts, err := findConflicts(srcFS, targetFS, rootDir)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) != 0 && (strategy == CsFail || strategy == CsUndefined) {
+		return e.NewFileConflictError(conflicts)
+	}
+	conflictSet := make(map[string]bool, len(conflicts))
+	for _, curPath := range conflicts {
+		conflictSet[curPath] = true
+	}
+
+	err = afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return errors.WithStack(targetFS.MkdirAll(path, info.Mode().Perm()))
+		}
+
+		data, err := afero.ReadFile(srcFS, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		relPath = filepath.ToSlash(relPath)
+		if conflictSet[relPath] {
+			overwrite := strategy == CsOverwrite
+			if strategy == CsPrompt {
+				overwrite, err = t.promptConflict(cmd, targetFS, path, relPath, data)
+				if err != nil {
+					return err
+				}
+			}
+			if !overwrite {
+				lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Skipping existing file %s\n", relPath))
+				return nil
+			}
+			lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "Overwriting existing file %s\n", relPath))
+		}
+
+		// Make sure to preserve the file mode
+		err = afero.WriteFile(targetFS, path, data, info.Mode())
+		if err != nil {
+			return errors.Wrap(err, "Failed to generate project file "+path)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed generating project")
+	}
+	return nil
+}
+
+// findConflicts gets the paths, relative to rootDir, of every file in srcFS which
+// already exists in targetFS with different content
+func findConflicts(srcFS afero.Fs, targetFS afero.Fs, rootDir string) ([]string, error) {
+	var retval []string
+	err := afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		targetInfo, err := targetFS.Stat(path)
+		if err != nil {
+			// Any file that can't be found in the target can be safely generated
+			return nil
+		}
+		if !targetInfo.IsDir() {
+			identical, err := isSameContent(srcFS, targetFS, path)
+			if err != nil || identical {
+				return err
+			}
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		retval = append(retval, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed checking for conflicts with existing files")
+	}
+	return retval, nil
+}
+
+// isSameContent returns true if a file has the same contents in both file systems
+func isSameContent(srcFS afero.Fs, targetFS afero.Fs, path string) (bool, error) {
+	srcData, err := afero.ReadFile(srcFS, path)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	targetData, err := afero.ReadFile(targetFS, path)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return bytes.Equal(srcData, targetData), nil
+}
+
+// promptConflict asks the user whether an existing file should be overwritten by the
+// generated data, optionally showing the differences between the two first
+func (t *templateManager) promptConflict(cmd *cobra.Command, targetFS afero.Fs, path string, relPath string, data []byte) (bool, error) {
+	reader := t.getReader(cmd)
+	for {
+		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "File %s already exists. Overwrite? [y]es/[n]o/[d]iff (default: n): ", relPath))
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		case "d", "diff":
+			existing, err := afero.ReadFile(targetFS, path)
+			if err != nil {
+				return false, errors.WithStack(err)
+			}
+			diff := unifiedDiff(relPath+" (existing)", relPath+" (generated)", existing, data)
+			lib.SNF(fmt.Fprint(cmd.OutOrStdout(), diff))
+		default:
+			lib.SNF(fmt.Fprintln(cmd.OutOrStdout(), "Invalid selection: "+strings.TrimSpace(value)))
+		}
+	}
+}
+
+// unifiedDiff generates a description of the differences between two versions of the
+// contents of a file, in unified diff format
+func unifiedDiff(fromFile string, toFile string, from []byte, to []byte) string {
+	if isBinaryContent(from, false) || isBinaryContent(to, false) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", fromFile, toFile)
+	}
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	}
+	// Generating diffs of in-memory strings should never fail
+	retval, err := difflib.GetUnifiedDiffString(diff)
+	lib.SNF(err)
+	return retval
+}
@@ -0,0 +1,204 @@
+package templateManager
+
+import (
+	"bytes"
+	"testing"
+
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConflictStrategyStringConversion(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		source string
+		target ConflictStrategy
+	}{
+		"Fail": {
+			source: "fail",
+			target: CsFail,
+		},
+		"Skip": {
+			source: "skip",
+			target: CsSkip,
+		},
+		"Overwrite": {
+			source: "overwrite",
+			target: CsOverwrite,
+		},
+		"Prompt": {
+			source: "prompt",
+			target: CsPrompt,
+		},
+		"Empty string": {
+			source: "",
+			target: CsUndefined,
+		},
+		"Unknown": {
+			source: "fubar",
+			target: CsUnknown,
+		},
+	}
+	r.Equal(int(CsPrompt)+1, len(tests))
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var temp ConflictStrategy
+			temp.fromString(data.source)
+			r.Equal(data.target, temp)
+			if data.target != CsUnknown {
+				r.Equal(data.source, temp.String())
+			}
+		})
+	}
+}
+
+func Test_ConflictStrategySet(t *testing.T) {
+	r := require.New(t)
+
+	var temp ConflictStrategy
+	r.NoError(temp.Set("skip"))
+	r.Equal(CsSkip, temp)
+
+	err := temp.Set("fubar")
+	r.Error(err)
+	r.Contains(err.Error(), "fubar")
+	r.Equal(CsSkip, temp)
+}
+
+// newConflictingProjects creates a generated project and an existing project which
+// share one identical file and one file with different content
+func newConflictingProjects(r *require.Assertions) (afero.Fs, afero.Fs) {
+	srcFS := afero.NewMemMapFs()
+	r.NoError(afero.WriteFile(srcFS, "/proj/same.txt", []byte("same"), 0644))
+	r.NoError(afero.WriteFile(srcFS, "/proj/sub/changed.txt", []byte("generated\n"), 0644))
+	r.NoError(afero.WriteFile(srcFS, "/proj/sub/new.txt", []byte("new"), 0644))
+
+	targetFS := afero.NewMemMapFs()
+	r.NoError(afero.WriteFile(targetFS, "/proj/same.txt", []byte("same"), 0644))
+	r.NoError(afero.WriteFile(targetFS, "/proj/sub/changed.txt", []byte("existing\n"), 0644))
+	r.NoError(afero.WriteFile(targetFS, "/proj/other.txt", []byte("other"), 0644))
+	return srcFS, targetFS
+}
+
+func Test_MergeProject(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		strategy ConflictStrategy
+		input    string
+		expected string
+	}{
+		"Skip": {
+			strategy: CsSkip,
+			expected: "existing\n",
+		},
+		"Overwrite": {
+			strategy: CsOverwrite,
+			expected: "generated\n",
+		},
+		"Prompt accept": {
+			strategy: CsPrompt,
+			input:    "y\n",
+			expected: "generated\n",
+		},
+		"Prompt reject": {
+			strategy: CsPrompt,
+			input:    "\n",
+			expected: "existing\n",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a generated project which conflicts with an existing project
+			srcFS, targetFS := newConflictingProjects(r)
+
+			// When we merge the generated project into the existing one
+			output := new(bytes.Buffer)
+			cmd := cobra.Command{}
+			cmd.SetOut(output)
+			cmd.SetIn(bytes.NewBufferString(data.input))
+			tm := templateManager{}
+			err := tm.MergeProject(&cmd, srcFS, targetFS, "/proj", data.strategy)
+			r.NoError(err)
+
+			// Then conflicts should be resolved using the selected strategy
+			contents, err := afero.ReadFile(targetFS, "/proj/sub/changed.txt")
+			r.NoError(err)
+			a.Equal(data.expected, string(contents))
+			a.Contains(output.String(), "sub/changed.txt")
+
+			// and non-conflicting files should be left as is or generated
+			a.NotContains(output.String(), "same.txt")
+			exists, err := afero.Exists(targetFS, "/proj/other.txt")
+			r.NoError(err)
+			a.True(exists)
+			exists, err = afero.Exists(targetFS, "/proj/sub/new.txt")
+			r.NoError(err)
+			a.True(exists)
+		})
+	}
+}
+
+func Test_MergeProjectFail(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a generated project which conflicts with an existing project
+	srcFS, targetFS := newConflictingProjects(r)
+
+	// When we merge the generated project using the fail strategy
+	cmd := cobra.Command{}
+	cmd.SetOut(new(bytes.Buffer))
+	tm := templateManager{}
+	err := tm.MergeProject(&cmd, srcFS, targetFS, "/proj", CsFail)
+
+	// Then an error listing the conflicting files should be returned
+	r.ErrorIs(err, e.NewFileConflictError([]string{"sub/changed.txt"}))
+
+	// and nothing should be generated
+	exists, err := afero.Exists(targetFS, "/proj/sub/new.txt")
+	r.NoError(err)
+	a.False(exists)
+}
+
+func Test_MergeProjectPromptDiff(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a generated project which conflicts with an existing project
+	srcFS, targetFS := newConflictingProjects(r)
+
+	// When the user asks to see a diff before rejecting the change
+	output := new(bytes.Buffer)
+	cmd := cobra.Command{}
+	cmd.SetOut(output)
+	cmd.SetIn(bytes.NewBufferString("d\nfubar\nn\n"))
+	tm := templateManager{}
+	err := tm.MergeProject(&cmd, srcFS, targetFS, "/proj", CsPrompt)
+	r.NoError(err)
+
+	// Then the differences should be displayed
+	a.Contains(output.String(), "--- sub/changed.txt (existing)")
+	a.Contains(output.String(), "+++ sub/changed.txt (generated)")
+	a.Contains(output.String(), "-existing\n")
+	a.Contains(output.String(), "+generated\n")
+	a.Contains(output.String(), "Invalid selection: fubar")
+
+	// and the existing file should be preserved
+	contents, err := afero.ReadFile(targetFS, "/proj/sub/changed.txt")
+	r.NoError(err)
+	a.Equal("existing\n", string(contents))
+}
+
+func Test_unifiedDiffBinary(t *testing.T) {
+	a := assert.New(t)
+
+	result := unifiedDiff("old.png", "new.png", []byte{0x00, 0x01}, []byte("text"))
+	a.Equal("Binary files old.png and new.png differ\n", result)
+}
@@ -0,0 +1,96 @@
+package templateManager
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// FileDrift describes the changes made to a single file of a project
+type FileDrift struct {
+	// Path path of the file relative to the root folder of the project
+	Path string `json:"path"`
+	// Diff unified diff between the template output and the project file
+	Diff string `json:"diff"`
+}
+
+// DriftReport summary of the differences between a project and the output its template
+// produces using the args recorded in the project archive
+type DriftReport struct {
+	// Template name of the template the project was generated from
+	Template string `json:"template"`
+	// Revision revision of the template the project was compared against
+	Revision string `json:"revision"`
+	// Added paths of files found in the project which are not produced by the template
+	Added []string `json:"added"`
+	// Removed paths of files produced by the template which are missing from the project
+	Removed []string `json:"removed"`
+	// Modified files which differ from those produced by the template
+	Modified []FileDrift `json:"modified"`
+}
+
+// HasDrift returns true if the project differs from the output of its template
+func (d *DriftReport) HasDrift() bool {
+	return len(d.Added) != 0 || len(d.Removed) != 0 || len(d.Modified) != 0
+}
+
+// CompareProject compares a project found in rootDir of projectFS with the output of its
+// template, generated in rootDir of templateFS. The project archive and any Git metadata
+// are excluded from the comparison
+func CompareProject(templateFS afero.Fs, projectFS afero.Fs, rootDir string) (DriftReport, error) {
+	retval := DriftReport{
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []FileDrift{},
+	}
+	templateFiles, err := listFiles(templateFS, rootDir, isComparable)
+	if err != nil {
+		return retval, err
+	}
+	projectFiles, err := listFiles(projectFS, rootDir, isComparable)
+	if err != nil {
+		return retval, err
+	}
+
+	for relPath := range projectFiles {
+		if !templateFiles[relPath] {
+			retval.Added = append(retval.Added, relPath)
+		}
+	}
+	for relPath := range templateFiles {
+		if !projectFiles[relPath] {
+			retval.Removed = append(retval.Removed, relPath)
+			continue
+		}
+		path := filepath.Join(rootDir, filepath.FromSlash(relPath))
+		expected, err := afero.ReadFile(templateFS, path)
+		if err != nil {
+			return retval, err
+		}
+		actual, err := afero.ReadFile(projectFS, path)
+		if err != nil {
+			return retval, err
+		}
+		if !bytes.Equal(expected, actual) {
+			retval.Modified = append(retval.Modified, FileDrift{
+				Path: relPath,
+				Diff: unifiedDiff(relPath+" (template)", relPath+" (project)", expected, actual),
+			})
+		}
+	}
+
+	sort.Strings(retval.Added)
+	sort.Strings(retval.Removed)
+	sort.Slice(retval.Modified, func(i, j int) bool {
+		return retval.Modified[i].Path < retval.Modified[j].Path
+	})
+	return retval, nil
+}
+
+// isComparable checks to see whether a project file or folder should be compared with the
+// template output. Files managed by Rejigger and the Git metadata folder are excluded
+func isComparable(relPath string) bool {
+	return relPath != ArchiveFileName && relPath != ".git"
+}
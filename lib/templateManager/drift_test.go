@@ -0,0 +1,103 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompareProject(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given the output of a template
+	templateFS := newFileSystem(r, map[string]string{
+		"same.txt":     "same",
+		"changed.txt":  "original\n",
+		"removed.txt":  "removed",
+		"sub/same.txt": "same",
+	})
+	// and a project which has diverged from it
+	projectFS := newFileSystem(r, map[string]string{
+		"same.txt":        "same",
+		"changed.txt":     "changed\n",
+		"added.txt":       "added",
+		"sub/same.txt":    "same",
+		ArchiveFileName:   "template:",
+		".git/HEAD":       "ref: refs/heads/main",
+		".gitignore":      "*.bak",
+		"sub/.git/config": "",
+	})
+
+	// When we compare the project with the template output
+	report, err := CompareProject(templateFS, projectFS, "/proj")
+	r.NoError(err)
+
+	// Then the differences should be reported
+	a.True(report.HasDrift())
+	a.Equal([]string{".gitignore", "added.txt", "sub/.git/config"}, report.Added)
+	a.Equal([]string{"removed.txt"}, report.Removed)
+	r.Len(report.Modified, 1)
+	a.Equal("changed.txt", report.Modified[0].Path)
+	a.Contains(report.Modified[0].Diff, "-original\n")
+	a.Contains(report.Modified[0].Diff, "+changed\n")
+}
+
+func Test_CompareProjectNoDrift(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project which matches the output of its template
+	files := map[string]string{
+		"same.txt":     "same",
+		"sub/same.txt": "same",
+	}
+	templateFS := newFileSystem(r, files)
+	projectFS := newFileSystem(r, files)
+
+	// When we compare the project with the template output
+	report, err := CompareProject(templateFS, projectFS, "/proj")
+	r.NoError(err)
+
+	// Then no differences should be reported
+	a.False(report.HasDrift())
+	a.Empty(report.Added)
+	a.Empty(report.Removed)
+	a.Empty(report.Modified)
+}
+
+// openRecorder file system which records the path of every file and folder opened
+type openRecorder struct {
+	afero.Fs
+	opened []string
+}
+
+func (o *openRecorder) Open(name string) (afero.File, error) {
+	o.opened = append(o.opened, name)
+	return o.Fs.Open(name)
+}
+
+func Test_CompareProjectSkipsGitFolder(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a project containing Git metadata
+	files := map[string]string{
+		"same.txt":             "same",
+		".git/HEAD":            "ref: refs/heads/main",
+		".git/refs/heads/main": "abc123",
+	}
+	templateFS := newFileSystem(r, map[string]string{"same.txt": "same"})
+	projectFS := &openRecorder{Fs: newFileSystem(r, files)}
+
+	// When we compare the project with the template output
+	report, err := CompareProject(templateFS, projectFS, "/proj")
+	r.NoError(err)
+
+	// Then the Git metadata folder should never be read
+	a.False(report.HasDrift())
+	a.NotContains(projectFS.opened, "/proj/.git")
+	a.NotEmpty(projectFS.opened)
+}
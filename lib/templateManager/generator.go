@@ -1,10 +1,13 @@
 package templateManager
 
 import (
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 
+	"github.com/TheFriendlyCoder/rejigger/lib"
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
 	"github.com/flosch/pongo2/v6"
 	"github.com/pkg/errors"
@@ -12,13 +15,30 @@ import (
 )
 
 // generate applies a set of user defined options (ie: the 'context') to a set of template
-// files stored in srcPath, and produces a complete project in the targetPath with the
-// user defined parameters applied throughout
-func generate(srcFS afero.Fs, templateOptions ao.TemplateOptions, targetPath string, context map[string]any) error {
+// files stored in srcFS, and produces a complete project in the targetPath of targetFS
+// with the user defined parameters applied throughout
+func generate(srcFS afero.Fs, targetFS afero.Fs, templateOptions ao.TemplateOptions, templateData TemplateData, targetPath string, context map[string]any) error {
 	rootDir := templateOptions.GetProjectRoot()
 
+	disabledPaths, err := getDisabledPaths(templateData.Features, context)
+	if err != nil {
+		return err
+	}
+	rawPaths, err := compileGlobs(templateData.Raw)
+	if err != nil {
+		return err
+	}
+	textPaths, err := compileGlobs(templateData.Text)
+	if err != nil {
+		return err
+	}
+	exclusions, err := compileExclusions(templateData.Exclusions)
+	if err != nil {
+		return err
+	}
+
 	// loop through all files
-	err := afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+	err = afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
 		// If walk encountered an error attempting to enumerate the file system object
 		// we are processing, it tells us here. For now we just assume we can not proceed
 		// if we hit this condition.
@@ -28,11 +48,6 @@ func generate(srcFS afero.Fs, templateOptions ao.TemplateOptions, targetPath str
 			return err
 		}
 
-		// Skip excluded files
-		if templateOptions.IsFileExcluded(path) {
-			return nil
-		}
-
 		relPath, err := filepath.Rel(rootDir, path)
 		if err != nil {
 			return errors.WithStack(err)
@@ -45,6 +60,15 @@ func generate(srcFS afero.Fs, templateOptions ao.TemplateOptions, targetPath str
 		if relPath == ".rejig.yml" {
 			return nil
 		}
+		// Skip excluded files, and files associated with disabled features
+		if templateOptions.IsFileExcluded(path, filepath.ToSlash(relPath)) ||
+			isPathMatched(exclusions, relPath) ||
+			isPathMatched(disabledPaths, relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 
 		// apply template to the Path being processed
 		newOutputPath, err := processPath(relPath, targetPath, context)
@@ -54,9 +78,14 @@ func generate(srcFS afero.Fs, templateOptions ao.TemplateOptions, targetPath str
 
 		// Generate output content
 		if info.IsDir() {
-			err = createOutputDir(newOutputPath, info.Mode())
+			err = createOutputDir(targetFS, newOutputPath, info.Mode())
 		} else {
-			err = createOutputFile(srcFS, path, newOutputPath, info.Mode(), context)
+			var applyTemplate bool
+			applyTemplate, err = shouldApplyTemplate(srcFS, path, relPath, rawPaths, textPaths)
+			if err != nil {
+				return err
+			}
+			err = createOutputFile(srcFS, targetFS, path, newOutputPath, info.Mode(), context, applyTemplate)
 		}
 		return err
 	})
@@ -66,6 +95,84 @@ func generate(srcFS afero.Fs, templateOptions ao.TemplateOptions, targetPath str
 	return nil
 }
 
+// getDisabledPaths gets the path patterns for every template feature whose condition is
+// not met by the given user defined options
+func getDisabledPaths(features []FeatureData, context map[string]any) ([]*regexp.Regexp, error) {
+	var retval []*regexp.Regexp
+	for _, curFeature := range features {
+		enabled, err := evaluateCondition(curFeature.When, context)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to check condition for feature "+curFeature.Name)
+		}
+		if enabled {
+			continue
+		}
+		patterns, err := compileGlobs(curFeature.Paths)
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, patterns...)
+	}
+	return retval, nil
+}
+
+// compileGlobs converts a list of glob patterns into their equivalent regular expressions
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	retval := make([]*regexp.Regexp, 0, len(patterns))
+	for _, curPattern := range patterns {
+		expr, err := lib.CompileGlob(curPattern)
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, expr)
+	}
+	return retval, nil
+}
+
+// isPathMatched returns true if the given path, relative to the template root, matches
+// any of the given path patterns
+func isPathMatched(patterns []*regexp.Regexp, relPath string) bool {
+	slashPath := filepath.ToSlash(relPath)
+	for _, curPattern := range patterns {
+		if curPattern.MatchString(slashPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileExclusions converts a list of file exclusion patterns into their equivalent
+// regular expressions
+func compileExclusions(patterns []string) ([]*regexp.Regexp, error) {
+	retval := make([]*regexp.Regexp, 0, len(patterns))
+	for _, curPattern := range patterns {
+		expr, err := lib.CompileExclusion(curPattern)
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, expr)
+	}
+	return retval, nil
+}
+
+// shouldApplyTemplate returns true if template args should be applied to the contents of
+// the given file. Files matching the raw path patterns are never templated, and files
+// matching the text path patterns are always templated. All other files are templated
+// unless they appear to contain binary content
+func shouldApplyTemplate(srcFS afero.Fs, path string, relPath string, rawPaths []*regexp.Regexp, textPaths []*regexp.Regexp) (bool, error) {
+	if isPathMatched(rawPaths, relPath) {
+		return false, nil
+	}
+	if isPathMatched(textPaths, relPath) {
+		return true, nil
+	}
+	isBinary, err := isBinaryFile(srcFS, path)
+	if err != nil {
+		return false, err
+	}
+	return !isBinary, nil
+}
+
 // processPath applies template processor to a folder name
 func processPath(relPath string, targetPath string, context map[string]any) (string, error) {
 	tpl, err := pongo2.FromString(relPath)
@@ -80,17 +187,22 @@ func processPath(relPath string, targetPath string, context map[string]any) (str
 }
 
 // createOutputDir applies template processor to a directory
-func createOutputDir(newOutputPath string, mode os.FileMode) error {
+func createOutputDir(targetFS afero.Fs, newOutputPath string, mode os.FileMode) error {
 	// Make sure to preserve the file mode
-	err := os.MkdirAll(newOutputPath, mode)
+	err := targetFS.MkdirAll(newOutputPath, mode)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-// createOutputFile applies template processor to a file
-func createOutputFile(srcFS afero.Fs, originalPath string, newOutputPath string, mode os.FileMode, context map[string]any) error {
+// createOutputFile applies template processor to a file. If applyTemplate is false
+// the file contents are copied verbatim
+func createOutputFile(srcFS afero.Fs, targetFS afero.Fs, originalPath string, newOutputPath string, mode os.FileMode, context map[string]any, applyTemplate bool) error {
+	if !applyTemplate {
+		return copyOutputFile(srcFS, targetFS, originalPath, newOutputPath, mode)
+	}
+
 	// Read in the original file contents
 	var data []byte
 	data, err := afero.ReadFile(srcFS, originalPath)
@@ -112,9 +224,33 @@ func createOutputFile(srcFS afero.Fs, originalPath string, newOutputPath string,
 
 	// Write processed output to new file location
 	// making sure to preserve the file mode in the process
-	err = os.WriteFile(newOutputPath, []byte(newData), mode)
+	err = afero.WriteFile(targetFS, newOutputPath, []byte(newData), mode)
 	if err != nil {
 		return errors.Wrap(err, "Failed to generate project file "+newOutputPath)
 	}
 	return nil
 }
+
+// copyOutputFile streams the contents of a file to a new location without modification
+func copyOutputFile(srcFS afero.Fs, targetFS afero.Fs, originalPath string, newOutputPath string, mode os.FileMode) error {
+	src, err := srcFS.Open(originalPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Make sure to preserve the file mode
+	dest, err := targetFS.OpenFile(newOutputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		lib.SNF(src.Close())
+		return errors.Wrap(err, "Failed to generate project file "+newOutputPath)
+	}
+	_, copyErr := io.Copy(dest, src)
+	srcErr := src.Close()
+	destErr := dest.Close()
+	for _, err = range []error{copyErr, srcErr, destErr} {
+		if err != nil {
+			return errors.Wrap(err, "Failed to generate project file "+newOutputPath)
+		}
+	}
+	return nil
+}
@@ -55,7 +55,7 @@ func Test_basicGenerator(t *testing.T) {
 				"version":      expVersion,
 			}
 			fs := data.fileSystem
-			err = generate(fs, options, tmpDir, context)
+			err = generate(fs, afero.NewOsFs(), options, TemplateData{}, tmpDir, context)
 
 			r.NoError(err, "Failed to run generator")
 
@@ -107,7 +107,7 @@ func Test_generateWithExclusions(t *testing.T) {
 		"version":      expVersion,
 	}
 	fs := fileSystem
-	err = generate(fs, options, tmpDir, context)
+	err = generate(fs, afero.NewOsFs(), options, TemplateData{}, tmpDir, context)
 
 	r.NoError(err, "Failed to run generator")
 
@@ -127,3 +127,249 @@ func Test_generateWithExclusions(t *testing.T) {
 	act = filepath.Join(tmpDir, "MyProj", "main.txt")
 	a.NoFileExists(act)
 }
+
+func Test_generateWithFeatures(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	sourceDir := getProjectDir()
+	options := ao.TemplateOptions{
+		Source: sourceDir,
+		Type:   ao.TstLocal,
+		Name:   "MyTemplate",
+	}
+
+	// Given a template with one feature that is enabled and one that is disabled
+	templateData := TemplateData{
+		Features: []FeatureData{
+			{
+				Name:  "sources",
+				When:  "include_sources",
+				Paths: []string{"{{project_name}}/**"},
+			},
+			{
+				Name:  "versioning",
+				When:  "version != 'none'",
+				Paths: []string{"version.txt"},
+			},
+		},
+	}
+
+	// and an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// We attempt to run the generator
+	context := map[string]any{
+		"project_name":    "MyProj",
+		"version":         "1.6.9",
+		"include_sources": false,
+	}
+	err = generate(afero.NewOsFs(), afero.NewOsFs(), options, templateData, tmpDir, context)
+	r.NoError(err, "Failed to run generator")
+
+	// Files associated with the disabled feature should be skipped
+	a.NoDirExists(filepath.Join(tmpDir, "MyProj"))
+
+	// and all other files should be generated
+	a.FileExists(filepath.Join(tmpDir, "version.txt"))
+	a.FileExists(filepath.Join(tmpDir, ".gitignore"))
+}
+
+func Test_generateWithInvalidFeature(t *testing.T) {
+	r := require.New(t)
+
+	options := ao.TemplateOptions{
+		Source: getProjectDir(),
+		Type:   ao.TstLocal,
+		Name:   "MyTemplate",
+	}
+	templateData := TemplateData{
+		Features: []FeatureData{{
+			Name:  "broken",
+			When:  "version !=",
+			Paths: []string{"version.txt"},
+		}},
+	}
+
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	err = generate(afero.NewOsFs(), afero.NewOsFs(), options, templateData, tmpDir, map[string]any{})
+	r.Error(err)
+	r.Contains(err.Error(), "broken")
+}
+
+func Test_generateWithRawFiles(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template containing files that are not compatible with the template engine
+	srcDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(srcDir)
+
+	workflowText := "run: echo ${{ secrets.TOKEN }}\n"
+	brokenText := "{% if %}"
+	r.NoError(os.MkdirAll(filepath.Join(srcDir, ".github", "workflows"), 0700))
+	r.NoError(os.WriteFile(filepath.Join(srcDir, ".github", "workflows", "build.yml"), []byte(workflowText), 0600))
+	r.NoError(os.WriteFile(filepath.Join(srcDir, "{{project_name}}.j2"), []byte(brokenText), 0600))
+	r.NoError(os.WriteFile(filepath.Join(srcDir, "README.md"), []byte("{{project_name}}"), 0600))
+
+	options := ao.TemplateOptions{
+		Source: srcDir,
+		Type:   ao.TstLocal,
+		Name:   "MyTemplate",
+	}
+	templateData := TemplateData{
+		Raw: []string{".github/**", "*.j2"},
+	}
+
+	// and an empty output folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// When we run the generator
+	context := map[string]any{"project_name": "MyProj"}
+	err = generate(afero.NewOsFs(), afero.NewOsFs(), options, templateData, tmpDir, context)
+	r.NoError(err, "Failed to run generator")
+
+	// Then raw files should be copied verbatim
+	contents, err := os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "build.yml"))
+	r.NoError(err)
+	a.Equal(workflowText, string(contents))
+
+	// and the names of raw files should still be templated
+	contents, err = os.ReadFile(filepath.Join(tmpDir, "MyProj.j2"))
+	r.NoError(err)
+	a.Equal(brokenText, string(contents))
+
+	// and all other files should be templated
+	contents, err = os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	r.NoError(err)
+	a.Equal("MyProj", string(contents))
+}
+
+func Test_generateWithBinaryFiles(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template containing binary files
+	srcDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(srcDir)
+
+	binaryData := []byte("\x00\x01{{project_name}}\xff")
+	forcedText := "{{project_name}}"
+	r.NoError(os.WriteFile(filepath.Join(srcDir, "fixture.dat"), binaryData, 0600))
+	r.NoError(os.WriteFile(filepath.Join(srcDir, "sample.png"), []byte(forcedText), 0600))
+
+	options := ao.TemplateOptions{
+		Source: srcDir,
+		Type:   ao.TstLocal,
+		Name:   "MyTemplate",
+	}
+	// and a manifest that forces one of the binary files to be templated
+	templateData := TemplateData{
+		Text: []string{"*.png"},
+	}
+
+	// and an empty output folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// When we run the generator
+	context := map[string]any{"project_name": "MyProj"}
+	err = generate(afero.NewOsFs(), afero.NewOsFs(), options, templateData, tmpDir, context)
+	r.NoError(err, "Failed to run generator")
+
+	// Then binary files should be copied verbatim
+	contents, err := os.ReadFile(filepath.Join(tmpDir, "fixture.dat"))
+	r.NoError(err)
+	a.Equal(binaryData, contents)
+
+	// and files forced to be treated as text should be templated
+	contents, err = os.ReadFile(filepath.Join(tmpDir, "sample.png"))
+	r.NoError(err)
+	a.Equal("MyProj", string(contents))
+}
+
+func Test_generateWithManifestExclusions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with exclusions defined in the app options
+	options := ao.TemplateOptions{
+		Source:     getProjectDir(),
+		Type:       ao.TstLocal,
+		Name:       "MyTemplate",
+		Exclusions: []string{"^version.txt$"},
+	}
+	// and additional exclusions defined in the template manifest
+	templateData := TemplateData{
+		Exclusions: []string{"glob:{{project_name}}/"},
+	}
+
+	// and an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// When we run the generator
+	context := map[string]any{
+		"project_name": "MyProj",
+		"version":      "1.6.9",
+	}
+	err = generate(afero.NewOsFs(), afero.NewOsFs(), options, templateData, tmpDir, context)
+	r.NoError(err, "Failed to run generator")
+
+	// Then both sets of exclusions should be honoured
+	a.NoFileExists(filepath.Join(tmpDir, "version.txt"))
+	a.NoDirExists(filepath.Join(tmpDir, "MyProj"))
+	a.FileExists(filepath.Join(tmpDir, ".gitignore"))
+}
+
+func Test_generateToVirtualFilesystem(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	options := ao.TemplateOptions{
+		Source: getProjectDir(),
+		Type:   ao.TstLocal,
+		Name:   "MyTemplate",
+	}
+
+	// Given an in-memory target file system
+	targetFS := afero.NewMemMapFs()
+	targetPath := "/output"
+
+	// When we run the generator
+	context := map[string]any{
+		"project_name": "MyProj",
+		"version":      "1.6.9",
+	}
+	err := generate(afero.NewOsFs(), targetFS, options, TemplateData{}, targetPath, context)
+	r.NoError(err, "Failed to run generator")
+
+	// Then the generated project should be written to the target file system
+	exists, err := afero.DirExists(targetFS, filepath.Join(targetPath, "MyProj"))
+	r.NoError(err)
+	a.True(exists)
+	contents, err := afero.ReadFile(targetFS, filepath.Join(targetPath, "version.txt"))
+	r.NoError(err)
+	a.Contains(string(contents), "1.6.9")
+
+	// And the file modes from the template should be preserved
+	srcInfo, err := os.Stat(filepath.Join(getProjectDir(), "version.txt"))
+	r.NoError(err)
+	info, err := targetFS.Stat(filepath.Join(targetPath, "version.txt"))
+	r.NoError(err)
+	a.Equal(srcInfo.Mode(), info.Mode())
+
+	// And nothing should be written to the local file system
+	a.NoDirExists(targetPath)
+}
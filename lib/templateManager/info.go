@@ -0,0 +1,182 @@
+package templateManager
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ArgInfo summary of an arg supported by a template
+type ArgInfo struct {
+	// Name of the argument
+	Name string `json:"name" yaml:"name"`
+	// Description descriptive text explaining the purpose of the argument
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Type data type of the argument
+	Type string `json:"type" yaml:"type"`
+	// Default value used when the user doesn't provide one
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Choices values the user must select from, if any
+	Choices []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+	// When condition controlling whether the user is prompted for the argument
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+}
+
+// FeatureInfo summary of an optional feature supported by a template
+type FeatureInfo struct {
+	// Name unique identifier for the feature
+	Name string `json:"name" yaml:"name"`
+	// Description descriptive text explaining the purpose of the feature
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// When condition controlling whether the files associated with the feature are generated
+	When string `json:"when" yaml:"when"`
+}
+
+// TemplateFile describes a file which may be produced by a template
+type TemplateFile struct {
+	// Path path of the file, relative to the template root. The path may contain
+	// references to template args which are replaced when the project is generated
+	Path string `json:"path" yaml:"path"`
+	// Feature name of the feature the file is associated with, if the file is only
+	// generated when the feature is enabled
+	Feature string `json:"feature,omitempty" yaml:"feature,omitempty"`
+}
+
+// TemplateInfo summary of the contents of a template
+type TemplateInfo struct {
+	// SchemaVersion version of the format of the template manifest
+	SchemaVersion string `json:"schemaVersion" yaml:"schemaVersion"`
+	// AppVersion minimum version of the application needed to process the template
+	AppVersion string `json:"rejiggerVersion,omitempty" yaml:"rejiggerVersion,omitempty"`
+	// TemplateVersion version of the template
+	TemplateVersion string `json:"templateVersion,omitempty" yaml:"templateVersion,omitempty"`
+	// Description descriptive text explaining the purpose of the template
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Tags keywords describing the template
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Maintainer person or team who maintains the template
+	Maintainer string `json:"maintainer,omitempty" yaml:"maintainer,omitempty"`
+	// Language programming language used by projects generated from the template
+	Language string `json:"language,omitempty" yaml:"language,omitempty"`
+	// Args every arg supported by the template, in the order they are prompted for
+	Args []ArgInfo `json:"args" yaml:"args"`
+	// Features every optional feature supported by the template
+	Features []FeatureInfo `json:"features" yaml:"features"`
+	// Files every file the template may produce
+	Files []TemplateFile `json:"files" yaml:"files"`
+}
+
+// Describe gets a summary of the template being managed, describing the args it supports
+// and the files it may produce. Metadata like the description of the template are taken
+// from the template options, when provided, falling back to those defined in the manifest
+func (t *templateManager) Describe() (TemplateInfo, error) {
+	versions := t.manifestData.Versions
+	manifest := t.manifestData.Template
+	retval := TemplateInfo{
+		SchemaVersion:   versions.Schema.Original(),
+		AppVersion:      versions.Jigger.Original(),
+		TemplateVersion: versions.Template.Original(),
+		Description:     firstNonEmpty(t.Options.Description, manifest.Description),
+		Tags:            manifest.Tags,
+		Maintainer:      firstNonEmpty(t.Options.Maintainer, manifest.Maintainer),
+		Language:        firstNonEmpty(t.Options.Language, manifest.Language),
+		Args:            make([]ArgInfo, 0, len(t.manifestData.Template.Args)),
+		Features:        make([]FeatureInfo, 0, len(t.manifestData.Template.Features)),
+	}
+	for _, curArg := range t.manifestData.Template.Args {
+		argType := curArg.Type
+		if argType == AtUndefined {
+			argType = AtString
+		}
+		retval.Args = append(retval.Args, ArgInfo{
+			Name:        curArg.Name,
+			Description: curArg.Description,
+			Type:        argType.toString(),
+			Default:     curArg.Default,
+			Choices:     curArg.Choices,
+			When:        curArg.When,
+		})
+	}
+	for _, curFeature := range t.manifestData.Template.Features {
+		retval.Features = append(retval.Features, FeatureInfo{
+			Name:        curFeature.Name,
+			Description: curFeature.Description,
+			When:        curFeature.When,
+		})
+	}
+
+	if len(t.Options.Tags) != 0 {
+		retval.Tags = t.Options.Tags
+	}
+
+	var err error
+	retval.Files, err = t.listFiles()
+	return retval, err
+}
+
+// firstNonEmpty gets the first of the given values which is not empty
+func firstNonEmpty(values ...string) string {
+	for _, curValue := range values {
+		if len(curValue) != 0 {
+			return curValue
+		}
+	}
+	return ""
+}
+
+// listFiles gets every file the template may produce, excluding files which are never
+// generated like the template manifest, along with the feature each file belongs to
+func (t *templateManager) listFiles() ([]TemplateFile, error) {
+	rootDir := t.Options.GetProjectRoot()
+	exclusions, err := compileExclusions(t.manifestData.Template.Exclusions)
+	if err != nil {
+		return nil, err
+	}
+	features := t.manifestData.Template.Features
+	featurePaths := make([][]*regexp.Regexp, len(features))
+	for i, curFeature := range features {
+		if featurePaths[i], err = compileGlobs(curFeature.Paths); err != nil {
+			return nil, err
+		}
+	}
+
+	retval := []TemplateFile{}
+	err = afero.Walk(t.srcFilesystem, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if relPath == "." || relPath == manifestFileName {
+			return nil
+		}
+		if t.Options.IsFileExcluded(path, filepath.ToSlash(relPath)) || isPathMatched(exclusions, relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		curFile := TemplateFile{Path: filepath.ToSlash(relPath)}
+		for i, curPaths := range featurePaths {
+			if isPathMatched(curPaths, relPath) {
+				curFile.Feature = features[i].Name
+				break
+			}
+		}
+		retval = append(retval, curFile)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list files in template "+t.Options.GetName())
+	}
+	return retval, nil
+}
@@ -0,0 +1,132 @@
+package templateManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_templateManagerDescribe(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with args, features and excluded files
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		manifestFileName: `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.2
+template:
+  description: Sample project
+  tags: [sample, c]
+  maintainer: Template Team
+  language: c
+  args:
+    - name: project_name
+      description: Name of the project
+    - name: license
+      type: enum
+      choices: [MIT, GPL]
+      default: MIT
+    - name: use_docker
+      type: bool
+    - name: registry
+      when: use_docker
+  features:
+    - name: docker
+      description: Docker support
+      when: use_docker
+      paths: [Dockerfile, "docker/**"]
+  exclusions:
+    - "glob:notes.txt"
+`,
+		"{{project_name}}/main.c": "",
+		"Dockerfile":              "",
+		"docker/build.sh":         "",
+		"notes.txt":               "",
+	}
+	for name, contents := range files {
+		curPath := filepath.Join(tmpDir, filepath.FromSlash(name))
+		r.NoError(os.MkdirAll(filepath.Dir(curPath), 0700))
+		r.NoError(os.WriteFile(curPath, []byte(contents), 0600))
+	}
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+	tm, err := New(options)
+	r.NoError(err)
+
+	// When we describe the template
+	info, err := tm.Describe()
+
+	// Then the manifest details should be summarized
+	r.NoError(err)
+	a.Equal("1.0", info.SchemaVersion)
+	a.Equal("0.0.1", info.AppVersion)
+	a.Equal("1.2", info.TemplateVersion)
+	a.Equal("Sample project", info.Description)
+	a.Equal([]string{"sample", "c"}, info.Tags)
+	a.Equal("Template Team", info.Maintainer)
+	a.Equal("c", info.Language)
+	a.Equal([]ArgInfo{
+		{Name: "project_name", Description: "Name of the project", Type: "string"},
+		{Name: "license", Type: "enum", Default: "MIT", Choices: []string{"MIT", "GPL"}},
+		{Name: "use_docker", Type: "bool"},
+		{Name: "registry", Type: "string", When: "use_docker"},
+	}, info.Args)
+	a.Equal([]FeatureInfo{{Name: "docker", Description: "Docker support", When: "use_docker"}}, info.Features)
+
+	// and every file the template may produce should be listed with its feature
+	a.ElementsMatch([]TemplateFile{
+		{Path: "{{project_name}}/main.c"},
+		{Path: "Dockerfile", Feature: "docker"},
+		{Path: "docker/build.sh", Feature: "docker"},
+	}, info.Files)
+}
+
+func Test_templateManagerDescribeOptionsMetadata(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template whose manifest defines metadata
+	tmpDir := t.TempDir()
+	manifest := `
+versions:
+  schema: 1.0
+template:
+  description: Manifest description
+  tags: [manifest]
+  maintainer: Manifest Team
+  language: c
+`
+	r.NoError(os.WriteFile(filepath.Join(tmpDir, manifestFileName), []byte(manifest), 0600))
+
+	// and template options which override some of the metadata
+	options := ao.TemplateOptions{
+		Source:      tmpDir,
+		Name:        "MyName",
+		Type:        ao.TstLocal,
+		Description: "Inventory description",
+		Tags:        []string{"inventory"},
+	}
+	tm, err := New(options)
+	r.NoError(err)
+
+	// When we describe the template
+	info, err := tm.Describe()
+
+	// Then metadata from the template options should take precedence
+	r.NoError(err)
+	a.Equal("Inventory description", info.Description)
+	a.Equal([]string{"inventory"}, info.Tags)
+	a.Equal("Manifest Team", info.Maintainer)
+	a.Equal("c", info.Language)
+}
@@ -0,0 +1,302 @@
+package templateManager
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// variableRef reference to a variable found in a template
+type variableRef struct {
+	// Name name of the variable being referenced
+	Name string
+	// Line line number, starting at 1, where the reference was found
+	Line int
+}
+
+var (
+	// tagPattern matches the variable and block tags in a template
+	tagPattern = regexp.MustCompile(`(?s)\{\{(.*?)\}\}|\{%(.*?)%\}`)
+	// ignoredPattern matches the parts of a template which are not processed, like comments
+	// and verbatim blocks
+	ignoredPattern = regexp.MustCompile(`(?s)\{#.*?#\}|\{%-?\s*verbatim\s*-?%\}.*?\{%-?\s*endverbatim\s*-?%\}`)
+	// stringPattern matches string literals in a template expression
+	stringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+	// identifierPattern matches identifiers in a template expression, along with any
+	// preceding character which indicates it is an attribute or filter name
+	identifierPattern = regexp.MustCompile(`([.|]?)\s*([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// expressionKeywords identifiers used by the template syntax which are not variables
+var expressionKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "is": true, "if": true, "else": true,
+	"true": true, "false": true, "True": true, "False": true, "None": true, "nil": true,
+	"reversed": true, "sorted": true, "as": true, "only": true,
+}
+
+// builtinNames functions and variables provided by the template engine itself
+var builtinNames = map[string]bool{
+	"forloop": true, "range": true, "super": true, "caller": true,
+}
+
+// expressionTags tags whose arguments are template expressions which may reference
+// variables. The arguments of all other tags, like block names, are ignored
+var expressionTags = map[string]bool{
+	"if": true, "elif": true, "for": true, "set": true, "with": true,
+}
+
+// LintTemplate checks the definition of a template found in rootDir of srcFS for problems.
+// The manifest is parsed in strict mode, then every file name and templated file is
+// compiled to check for syntax errors. Variables referenced by the template which are not
+// declared as args, and args which are never referenced, are reported. A description of
+// every problem found is returned, prefixed with the path of the file, relative to rootDir,
+// and the line number where it was found when known
+func LintTemplate(srcFS afero.Fs, rootDir string) ([]string, error) {
+	manifest, err := parseManifest(srcFS, filepath.Join(rootDir, manifestFileName), true)
+	if err != nil {
+		return nil, err
+	}
+	rawPaths, err := compileGlobs(manifest.Template.Raw)
+	if err != nil {
+		return nil, err
+	}
+	textPaths, err := compileGlobs(manifest.Template.Text)
+	if err != nil {
+		return nil, err
+	}
+	exclusions, err := compileExclusions(manifest.Template.Exclusions)
+	if err != nil {
+		return nil, err
+	}
+
+	var retval []string
+	declared := map[string]bool{}
+	for _, curArg := range manifest.Template.Args {
+		declared[curArg.Name] = true
+	}
+	used := map[string]bool{}
+	// checkRefs records the variables referenced by a template, reporting any which
+	// have not been declared as args
+	checkRefs := func(source string, refs []variableRef) {
+		for _, curRef := range refs {
+			used[curRef.Name] = true
+			if !declared[curRef.Name] {
+				retval = append(retval, formatProblem(source, curRef.Line,
+					fmt.Sprintf("variable %s is not declared as a template arg", curRef.Name)))
+			}
+		}
+	}
+
+	// Expressions in the manifest may reference args as well
+	for _, curArg := range manifest.Template.Args {
+		checkRefs(manifestFileName, findVariables(curArg.Default))
+		checkRefs(manifestFileName, findExpressionVariables(curArg.When, 0))
+	}
+	for _, curFeature := range manifest.Template.Features {
+		checkRefs(manifestFileName, findExpressionVariables(curFeature.When, 0))
+	}
+	for _, curMigration := range manifest.Template.Migrations {
+		for _, curHook := range curMigration.Hooks {
+			checkRefs(manifestFileName, findVariables(curHook))
+		}
+	}
+
+	err = afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if relPath == "." || relPath == manifestFileName {
+			return nil
+		}
+		if isPathMatched(exclusions, relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if _, err = pongo2.FromString(relPath); err != nil {
+			retval = append(retval, formatProblem(relPath, 0, "invalid template in path: "+describeTemplateError(err)))
+		} else {
+			checkRefs(relPath, findVariables(relPath))
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		applyTemplate, err := shouldApplyTemplate(srcFS, path, relPath, rawPaths, textPaths)
+		if err != nil || !applyTemplate {
+			return err
+		}
+		data, err := afero.ReadFile(srcFS, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err = pongo2.FromString(string(data)); err != nil {
+			retval = append(retval, formatProblem(relPath, templateErrorLine(err), describeTemplateError(err)))
+			return nil
+		}
+		checkRefs(relPath, findVariables(string(data)))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to lint template "+rootDir)
+	}
+
+	for _, curArg := range manifest.Template.Args {
+		if !used[curArg.Name] {
+			retval = append(retval, formatProblem(manifestFileName, 0,
+				fmt.Sprintf("arg %s is never used by the template", curArg.Name)))
+		}
+	}
+	return retval, nil
+}
+
+// formatProblem describes a problem found in a template file
+func formatProblem(source string, line int, message string) string {
+	if line > 0 {
+		return fmt.Sprintf("%s:%d: %s", source, line, message)
+	}
+	return fmt.Sprintf("%s: %s", source, message)
+}
+
+// templateErrorLine gets the line number associated with a template syntax error, or 0
+// if the line number is not known
+func templateErrorLine(err error) int {
+	var tplErr *pongo2.Error
+	if errors.As(err, &tplErr) {
+		return tplErr.Line
+	}
+	return 0
+}
+
+// describeTemplateError gets a description of a template syntax error, without the
+// location details which are reported separately
+func describeTemplateError(err error) string {
+	var tplErr *pongo2.Error
+	if errors.As(err, &tplErr) && tplErr.OrigError != nil {
+		return tplErr.OrigError.Error()
+	}
+	return err.Error()
+}
+
+// findVariables gets all of the variables referenced by the tags in a template, excluding
+// variables defined by the template itself, ordered by their location in the template
+func findVariables(text string) []variableRef {
+	// Blank out the parts of the template which aren't processed, preserving line breaks
+	// so line numbers are still accurate
+	text = ignoredPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return strings.Repeat("\n", strings.Count(match, "\n"))
+	})
+
+	var retval []variableRef
+	locals := map[string]bool{}
+	for _, curMatch := range tagPattern.FindAllStringSubmatchIndex(text, -1) {
+		line := strings.Count(text[:curMatch[0]], "\n") + 1
+		if curMatch[2] >= 0 {
+			retval = append(retval, findExpressionVariables(text[curMatch[2]:curMatch[3]], line)...)
+			continue
+		}
+		expr := stringPattern.ReplaceAllString(text[curMatch[4]:curMatch[5]], " ")
+		expr = strings.Trim(expr, "- \t\r\n")
+		tag, args, _ := strings.Cut(expr, " ")
+		if tag == "macro" {
+			// macro name(param, other=default)
+			retval = append(retval, findMacroVariables(args, line, locals)...)
+			continue
+		}
+		if !expressionTags[tag] {
+			continue
+		}
+		switch tag {
+		case "for":
+			// for x, y in items
+			names, items, _ := strings.Cut(args, " in ")
+			for _, curName := range strings.Split(names, ",") {
+				locals[strings.TrimSpace(curName)] = true
+			}
+			args = items
+		case "set":
+			// set x = value
+			name, value, _ := strings.Cut(args, "=")
+			locals[strings.TrimSpace(name)] = true
+			args = value
+		case "with":
+			// with x=value y=other
+			var values []string
+			for _, curPair := range strings.Fields(args) {
+				name, value, found := strings.Cut(curPair, "=")
+				if found {
+					locals[name] = true
+					values = append(values, value)
+				} else {
+					values = append(values, curPair)
+				}
+			}
+			args = strings.Join(values, " ")
+		}
+		retval = append(retval, findExpressionVariables(args, line)...)
+	}
+
+	filtered := make([]variableRef, 0, len(retval))
+	for _, curRef := range retval {
+		if !locals[curRef.Name] {
+			filtered = append(filtered, curRef)
+		}
+	}
+	return filtered
+}
+
+// findMacroVariables records the name and parameters of a macro definition, like
+// "greet(who, greeting='hello')", as local variables and gets the variables referenced by
+// the default values of its parameters
+func findMacroVariables(definition string, line int, locals map[string]bool) []variableRef {
+	name, params, _ := strings.Cut(definition, "(")
+	locals[strings.TrimSpace(name)] = true
+	params, _, _ = strings.Cut(params, ")")
+
+	var retval []variableRef
+	for _, curParam := range strings.Split(params, ",") {
+		param, value, found := strings.Cut(curParam, "=")
+		locals[strings.TrimSpace(param)] = true
+		if found {
+			retval = append(retval, findExpressionVariables(value, line)...)
+		}
+	}
+	return retval
+}
+
+// findExpressionVariables gets all of the variables referenced in a template expression,
+// like "use_docker and ci != 'none'", reporting them as being found on the given line
+func findExpressionVariables(expr string, line int) []variableRef {
+	expr = stringPattern.ReplaceAllString(expr, " ")
+	var retval []variableRef
+	seen := map[string]bool{}
+	// the two identifiers preceding the current one, used to detect tests
+	var previous [2]string
+	for _, curMatch := range identifierPattern.FindAllStringSubmatch(expr, -1) {
+		name := curMatch[2]
+		// Names following "is" or "is not" are tests, like "value is defined"
+		isTest := name != "not" && (previous[1] == "is" || previous[0] == "is" && previous[1] == "not")
+		previous = [2]string{previous[1], name}
+		// Skip attribute and filter names, keywords, builtins, tests and names we've
+		// already reported
+		if len(curMatch[1]) != 0 || expressionKeywords[name] || builtinNames[name] || isTest || seen[name] {
+			continue
+		}
+		seen[name] = true
+		retval = append(retval, variableRef{Name: name, Line: line})
+	}
+	return retval
+}
@@ -0,0 +1,167 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintManifest = `
+versions:
+  schema: 1.0
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      description: Name of the project
+    - name: use_docker
+      type: bool
+    - name: author
+      default: "{{ project_name|lower }} team"
+    - name: unused
+  features:
+    - name: docker
+      when: use_docker and ci != 'none'
+      paths: [Dockerfile]
+  raw:
+    - raw.txt
+  exclusions:
+    - "glob:docs/"
+`
+
+func Test_LintTemplate(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template containing several problems
+	srcFS := afero.NewMemMapFs()
+	files := map[string]string{
+		".rejig.yml":              lintManifest,
+		"{{project_name}}/main.c": "/* {{ author }} */\n{% for item in items %}{{ item.name|upper }}{% endfor %}\n",
+		"broken.txt":              "line 1\nline 2\n{% if project_name %}\nunterminated\n",
+		"raw.txt":                 "{{ ignored }} {% if %}",
+		"docs/readme.md":          "{{ excluded }}",
+		"comments.txt":            "{# {{ commented }} #}\n{% verbatim %}{{ verbatim }}{% endverbatim %}\n{% set local = 'x' %}{{ local }}",
+	}
+	for name, contents := range files {
+		r.NoError(afero.WriteFile(srcFS, "/tmpl/"+name, []byte(contents), 0644))
+	}
+
+	// When we lint the template
+	problems, err := LintTemplate(srcFS, "/tmpl")
+	r.NoError(err)
+
+	// Then every problem should be reported with its location
+	a.ElementsMatch([]string{
+		".rejig.yml: variable ci is not declared as a template arg",
+		"{{project_name}}/main.c:2: variable items is not declared as a template arg",
+		".rejig.yml: arg unused is never used by the template",
+	}, filterProblems(problems, "broken.txt"))
+
+	// and syntax errors should be reported with the line number they were found on
+	a.Len(filterProblems(problems, ""), 1+3)
+	found := false
+	for _, curProblem := range problems {
+		if len(curProblem) > 11 && curProblem[:11] == "broken.txt:" {
+			found = true
+			a.Regexp(`^broken.txt:\d+: `, curProblem)
+		}
+	}
+	a.True(found)
+}
+
+// filterProblems removes all problems reported for a specific file
+func filterProblems(problems []string, file string) []string {
+	var retval []string
+	for _, curProblem := range problems {
+		if len(file) != 0 && len(curProblem) > len(file) && curProblem[:len(file)+1] == file+":" {
+			continue
+		}
+		retval = append(retval, curProblem)
+	}
+	return retval
+}
+
+func Test_LintTemplateSample(t *testing.T) {
+	r := require.New(t)
+
+	// Given a sample template with no problems
+	srcDir := getProjectDir()
+
+	// When we lint the template
+	problems, err := LintTemplate(afero.NewOsFs(), srcDir)
+
+	// Then no problems should be found
+	r.NoError(err)
+	r.Empty(problems)
+}
+
+func Test_findVariables(t *testing.T) {
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		text     string
+		expected []variableRef
+	}{
+		"Simple variable": {
+			text:     "{{ name }}",
+			expected: []variableRef{{Name: "name", Line: 1}},
+		},
+		"Filters and attributes": {
+			text:     "\n{{ person.name|default:fallback|lower }}",
+			expected: []variableRef{{Name: "person", Line: 2}, {Name: "fallback", Line: 2}},
+		},
+		"Conditions": {
+			text:     "{% if a and not b or c == 'd' %}{% elif e %}{% endif %}",
+			expected: []variableRef{{Name: "a", Line: 1}, {Name: "b", Line: 1}, {Name: "c", Line: 1}, {Name: "e", Line: 1}},
+		},
+		"Loop variables": {
+			text:     "{% for k, v in items reversed %}{{ k }}{{ v }}{{ forloop.Counter }}{% endfor %}",
+			expected: []variableRef{{Name: "items", Line: 1}},
+		},
+		"With variables": {
+			text:     "{% with total=count other=1 %}{{ total }}{% endwith %}",
+			expected: []variableRef{{Name: "count", Line: 1}},
+		},
+		"Block names": {
+			text:     "{% block content %}{{ title }}{% endblock %}",
+			expected: []variableRef{{Name: "title", Line: 1}},
+		},
+		"Filter tags": {
+			text:     "{% filter upper %}text{% endfilter %}",
+			expected: []variableRef{},
+		},
+		"Autoescape tags": {
+			text:     "{% autoescape off %}{{ html }}{% endautoescape %}",
+			expected: []variableRef{{Name: "html", Line: 1}},
+		},
+		"Macro parameters": {
+			text:     "{% macro greet(who, greeting=salutation) %}{{ greeting }} {{ who }}{% endmacro %}{{ greet(name) }}",
+			expected: []variableRef{{Name: "salutation", Line: 1}, {Name: "name", Line: 1}},
+		},
+		"Builtin functions": {
+			text:     "{% for i in range(3) %}{{ i }}{% endfor %}",
+			expected: []variableRef{},
+		},
+		"Tests": {
+			text:     "{% if a is defined and b is not none %}{% endif %}",
+			expected: []variableRef{{Name: "a", Line: 1}, {Name: "b", Line: 1}},
+		},
+		"Set variables": {
+			text:     "{% set total = count %}{{ total }}",
+			expected: []variableRef{{Name: "count", Line: 1}},
+		},
+		"Plain text": {
+			text:     "no template content here",
+			expected: []variableRef{},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			a.Equal(data.expected, findVariables(data.text))
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/TheFriendlyCoder/rejigger/lib"
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/flosch/pongo2/v6"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -32,18 +34,41 @@ type templateManager struct {
 	// srcFilesystem virtual file system to use when interacting with source files
 	// defining the content of the template we are managing by this struct
 	srcFilesystem afero.Fs
+	// srcRevision identifier for the revision of the template loaded into srcFilesystem,
+	// if the template source supports revisions
+	srcRevision string
+	// srcTag name of the tag identifying the release of the template loaded into
+	// srcFilesystem, when the release was selected using a version constraint
+	srcTag string
+	// inputReader buffered reader used to read responses to prompts from the user.
+	// It is shared by all prompts so input buffered by one prompt is not lost
+	inputReader *bufio.Reader
 }
 
 // New constructs new instances of our template manager, which allows the caller
 // to interact with a template in various ways
 func New(options ao.TemplateOptions) (templateManager, error) {
+	return NewAtRevision(options, "")
+}
+
+// NewAtRevision constructs new instances of our template manager which interact with a
+// specific revision of a template, such as a Git commit hash or tag. If no revision is
+// given the latest revision of the template is used
+func NewAtRevision(options ao.TemplateOptions, revision string) (templateManager, error) {
 	// Initialize empty options
 	retval := templateManager{}
 	retval.Options = options
 	retval.templateContext = map[string]any{}
 
 	var err error
-	retval.srcFilesystem, err = options.GetFilesystem()
+	if revision == "" && options.Version != "" {
+		// Resolve the release to use up front, so we can record which one was selected
+		if revision, err = options.ResolveVersion(); err != nil {
+			return retval, err
+		}
+		retval.srcTag = revision
+	}
+	retval.srcFilesystem, retval.srcRevision, err = options.GetFilesystemRevision(revision)
 	if err != nil {
 		return retval, err
 	}
@@ -55,42 +80,257 @@ func New(options ao.TemplateOptions) (templateManager, error) {
 		return retval, errors.WithStack(err)
 	}
 
-	retval.manifestData, err = parseManifest(retval.srcFilesystem, manifestPath)
+	retval.manifestData, err = parseManifest(retval.srcFilesystem, manifestPath, false)
 	if err != nil {
 		return retval, err
 	}
 	return retval, nil
 }
 
+// NewFromArchive constructs a template manager for the same revision of the template a
+// project was generated from, with all user defined options populated using the values
+// recorded in the project's archive file
+func NewFromArchive(archive ArchiveData) (templateManager, error) {
+	retval, err := NewAtRevision(archive.GetTemplateOptions(), archive.Template.Revision)
+	if err != nil {
+		return retval, err
+	}
+	retval.srcTag = archive.Template.Tag
+	if err = retval.SetParams(archive.Args); err != nil {
+		return retval, err
+	}
+	return retval, retval.ResolveParams()
+}
+
+// GetTag gets the name of the tag identifying the release of the template being managed,
+// when the release was selected using a version constraint
+func (t *templateManager) GetTag() string {
+	return t.srcTag
+}
+
+// GetRevision gets the identifier for the revision of the template being managed, if the
+// template source supports revisions
+func (t *templateManager) GetRevision() string {
+	return t.srcRevision
+}
+
+// SetParams pre-populates values for user defined options supported by this template,
+// so the user will not be prompted for them. An error is returned if any of the
+// named options are not supported by the template, or if any of the values are not
+// compatible with the data type of their respective options
+func (t *templateManager) SetParams(values map[string]string) error {
+	for name, value := range values {
+		arg := t.findArg(name)
+		if arg == nil {
+			return e.NewSimpleError("Template does not support arg " + name)
+		}
+		parsedValue, err := parseArgValue(*arg, value)
+		if err != nil {
+			return err
+		}
+		t.templateContext[name] = parsedValue
+	}
+	return nil
+}
+
 // GatherParams iterates over all user defined options supported by this
-// template, and prompts the user for values for them all
+// template, and prompts the user for values for any which have not already been
+// provided. If the user provides a value that is not compatible with the data type
+// of the argument, they are prompted again until a valid value is given. If the user
+// provides no value the default for the argument, if any, is used instead. Options
+// whose conditions are not met by the values provided for earlier options are skipped
 func (t *templateManager) GatherParams(cmd *cobra.Command) error {
 	// TODO: Consider moving this functionality into calling class
-	// TODO: return as a no-op if there aren't any args to gather
-	reader := bufio.NewReader(cmd.InOrStdin())
+	reader := t.getReader(cmd)
 	for _, arg := range t.manifestData.Template.Args {
-		lib.SNF(fmt.Fprintf(cmd.OutOrStdout(), "%s(%s): ", arg.Description, arg.Name))
+		active, err := evaluateCondition(arg.When, t.templateContext)
+		if err != nil {
+			return errors.Wrap(err, "Failed to check condition for arg "+arg.Name)
+		}
+		if !active {
+			if err = skipArg(arg, t.templateContext); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := t.templateContext[arg.Name]; ok {
+			continue
+		}
+		defaultValue, err := renderDefault(arg, t.templateContext)
+		if err != nil {
+			return err
+		}
+		for {
+			lib.SNF(fmt.Fprint(cmd.OutOrStdout(), promptText(arg, defaultValue)))
+
+			// NOTE: Scanln method apparently doesn't work for reading input strings that have
+			// spaces in them, so we use a read buffer here instead
+			value, err := reader.ReadString('\n')
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			// Here we need to trim white space from our input value to get rid
+			// of the trailing newline characters which are included in the read buffer
+			value = strings.TrimSpace(value)
+			if value == "" {
+				value = defaultValue
+			}
+			value = resolveChoice(arg, value)
+			parsedValue, err := parseArgValue(arg, value)
+			if err != nil {
+				lib.SNF(fmt.Fprintln(cmd.OutOrStdout(), err.Error()))
+				continue
+			}
+			t.templateContext[arg.Name] = parsedValue
+			break
+		}
+	}
+	return nil
+}
+
+// getReader gets the buffered reader used to read user input from the given command
+func (t *templateManager) getReader(cmd *cobra.Command) *bufio.Reader {
+	if t.inputReader == nil {
+		t.inputReader = bufio.NewReader(cmd.InOrStdin())
+	}
+	return t.inputReader
+}
+
+// ResolveParams populates values for all user defined options supported by this
+// template which have not already been provided, using the default values for each,
+// without prompting the user. An error is returned listing every option that
+// has no value and no default
+func (t *templateManager) ResolveParams() error {
+	// We work on a copy of the context so it is left untouched if any args are missing
+	context := make(map[string]any, len(t.templateContext))
+	for name, value := range t.templateContext {
+		context[name] = value
+	}
 
-		// NOTE: Scanln method apparently doesn't work for reading input strings that have
-		// spaces in them, so we use a read buffer here instead
-		value, err := reader.ReadString('\n')
+	var missing []string
+	for _, arg := range t.manifestData.Template.Args {
+		active, err := evaluateCondition(arg.When, context)
+		if err != nil {
+			return errors.Wrap(err, "Failed to check condition for arg "+arg.Name)
+		}
+		if !active {
+			if err = skipArg(arg, context); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := context[arg.Name]; ok {
+			continue
+		}
+		if len(arg.Default) == 0 {
+			missing = append(missing, arg.Name)
+			continue
+		}
+		value, err := defaultValue(arg, context)
 		if err != nil {
-			return errors.WithStack(err)
+			return err
 		}
-		// Here we need to trim white space from our input value to get rid
-		// of the trailing newline characters which are included in the read buffer
-		t.templateContext[arg.Name] = strings.TrimSpace(value)
+		context[arg.Name] = value
+	}
+	if len(missing) != 0 {
+		return e.NewMissingArgsError(missing)
 	}
+	t.templateContext = context
 	return nil
 }
 
-// Generate produces a new template based on the parameters defined in this
-// object, in the specified output folder
-func (t *templateManager) Generate(targetPath string) error {
-	fs, err := t.Options.GetFilesystem()
+// SetArchivedParams pre-populates values for user defined options supported by this
+// template using the values recorded in a project archive file. Unlike SetParams, values
+// for options which are not supported by this template are ignored, since they may have
+// been removed since the project was generated
+func (t *templateManager) SetArchivedParams(values map[string]string) error {
+	supported := map[string]string{}
+	for name, value := range values {
+		if t.findArg(name) != nil {
+			supported[name] = value
+		}
+	}
+	return t.SetParams(supported)
+}
+
+// findArg locates the definition for a user defined option supported by this template
+// Returns nil if the template has no option with the given name
+func (t *templateManager) findArg(name string) *ArgData {
+	return t.manifestData.findArg(name)
+}
+
+// renderDefault generates the default value for a template argument, applying
+// the values of any previously gathered args to the default value expression
+func renderDefault(arg ArgData, context map[string]any) (string, error) {
+	if !arg.hasComputedDefault() {
+		return arg.Default, nil
+	}
+	tpl, err := pongo2.FromString(arg.Default)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to load default value for arg "+arg.Name)
+	}
+	retval, err := tpl.Execute(context)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to render default value for arg "+arg.Name)
+	}
+	return retval, nil
+}
+
+// defaultValue generates the default value for a template argument, converted to
+// the native data type associated with the argument
+func defaultValue(arg ArgData, context map[string]any) (any, error) {
+	rendered, err := renderDefault(arg, context)
+	if err != nil {
+		return nil, err
+	}
+	return parseArgValue(arg, rendered)
+}
+
+// skipArg updates a set of template args to account for an arg whose condition was
+// not met. The arg is set to its default value if it has one, and removed otherwise
+func skipArg(arg ArgData, context map[string]any) error {
+	if len(arg.Default) == 0 {
+		delete(context, arg.Name)
+		return nil
+	}
+	value, err := defaultValue(arg, context)
 	if err != nil {
 		return err
 	}
+	context[arg.Name] = value
+	return nil
+}
+
+// promptText generates the text to display to the user when asking for the value
+// of a specific template argument. Args with a fixed set of choices are displayed
+// as a numbered menu
+func promptText(arg ArgData, defaultValue string) string {
+	retval := fmt.Sprintf("%s(%s)", arg.Description, arg.Name)
+	if len(arg.Choices) != 0 {
+		retval += ":\n"
+		for i, curChoice := range arg.Choices {
+			retval += fmt.Sprintf("  %d) %s\n", i+1, curChoice)
+		}
+		retval += fmt.Sprintf("Select 1-%d", len(arg.Choices))
+	} else {
+		switch arg.Type {
+		case AtUndefined, AtString:
+			// Free-form text needs no further explanation
+		case AtList:
+			retval += " [comma separated list]"
+		default:
+			retval += fmt.Sprintf(" [%s]", arg.Type.toString())
+		}
+	}
+	if defaultValue != "" {
+		retval += fmt.Sprintf(" (default: %s)", defaultValue)
+	}
+	return retval + ": "
+}
+
+// Generate produces a new template based on the parameters defined in this
+// object, in the specified output folder of the target file system
+func (t *templateManager) Generate(targetFS afero.Fs, targetPath string) error {
 	// TODO: add these support methods to the manager class as private methods
-	return generate(fs, t.Options, targetPath, t.templateContext)
+	return generate(t.srcFilesystem, targetFS, t.Options, t.manifestData.Template, targetPath, t.templateContext)
 }
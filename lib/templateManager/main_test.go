@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	ao "github.com/TheFriendlyCoder/rejigger/lib/applicationOptions"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -215,6 +218,380 @@ template:
 	}
 }
 
+func Test_templateManagerGatherTypedParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with typed args
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: use_docker
+      description: Enable docker support
+      type: bool
+    - name: port
+      description: Port number
+      type: int
+    - name: authors
+      description: Project authors
+      type: list`
+	configFile := path.Join(tmpDir, manifestFileName)
+	err = os.WriteFile(configFile, []byte(templateConfigText), 0600)
+	r.NoError(err)
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+
+	// and some user input that contains an invalid value for one of the args
+	output := new(bytes.Buffer)
+	fakeInput := new(bytes.Buffer)
+	_, err = fakeInput.WriteString("yes\nfubar\n8080\nJohn, Jane\n")
+	r.NoError(err)
+
+	cmd := cobra.Command{}
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetIn(fakeInput)
+
+	// when we process the user input
+	tm, err := New(options)
+	r.NoError(err)
+	err = tm.GatherParams(&cmd)
+	r.NoError(err)
+
+	// We expect the parsed parameters to be converted to their native types
+	a.Equal(true, tm.templateContext["use_docker"])
+	a.Equal(8080, tm.templateContext["port"])
+	a.Equal([]string{"John", "Jane"}, tm.templateContext["authors"])
+
+	// And the user should have been told about their invalid input, and re-prompted
+	a.Contains(output.String(), "Invalid value 'fubar' for port")
+	a.Equal(2, strings.Count(output.String(), "Port number(port) [int]: "))
+}
+
+func Test_templateManagerGatherParamsWithDefaults(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with literal and computed defaults
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      description: Name of the project
+    - name: package_name
+      description: Name of the package
+      default: "{{ project_name|lower }}"
+    - name: port
+      description: Port number
+      type: int
+      default: 8080`
+	configFile := path.Join(tmpDir, manifestFileName)
+	err = os.WriteFile(configFile, []byte(templateConfigText), 0600)
+	r.NoError(err)
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+
+	// and user input that accepts the defaults for all but the first arg
+	output := new(bytes.Buffer)
+	fakeInput := new(bytes.Buffer)
+	_, err = fakeInput.WriteString("MyProj\n\n\n")
+	r.NoError(err)
+
+	cmd := cobra.Command{}
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetIn(fakeInput)
+
+	// when we process the user input
+	tm, err := New(options)
+	r.NoError(err)
+	err = tm.GatherParams(&cmd)
+	r.NoError(err)
+
+	// We expect the default values to be used
+	a.Equal("MyProj", tm.templateContext["project_name"])
+	a.Equal("myproj", tm.templateContext["package_name"])
+	a.Equal(8080, tm.templateContext["port"])
+
+	// And the defaults should have been shown to the user
+	a.Contains(output.String(), "Name of the package(package_name) (default: myproj): ")
+	a.Contains(output.String(), "Port number(port) [int] (default: 8080): ")
+}
+
+func Test_templateManagerGatherParamsWithChoices(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with args that have a fixed set of choices
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: license
+      description: Project license
+      type: enum
+      choices: [MIT, GPL, Apache]
+    - name: ci
+      description: CI provider
+      choices: [github, gitlab]
+      default: gitlab`
+	configFile := path.Join(tmpDir, manifestFileName)
+	r.NoError(os.WriteFile(configFile, []byte(templateConfigText), 0600))
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+
+	// and user input with an invalid selection, followed by a numbered selection
+	// and finally accepting the default
+	output := new(bytes.Buffer)
+	fakeInput := new(bytes.Buffer)
+	_, err = fakeInput.WriteString("BSD\n3\n\n")
+	r.NoError(err)
+
+	cmd := cobra.Command{}
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetIn(fakeInput)
+
+	// when we process the user input
+	tm, err := New(options)
+	r.NoError(err)
+	err = tm.GatherParams(&cmd)
+	r.NoError(err)
+
+	// We expect the selected values to be stored
+	a.Equal("Apache", tm.templateContext["license"])
+	a.Equal("gitlab", tm.templateContext["ci"])
+
+	// And the user should have been shown a numbered menu
+	a.Contains(output.String(), "Project license(license):\n  1) MIT\n  2) GPL\n  3) Apache\nSelect 1-3: ")
+	a.Contains(output.String(), "Select 1-2 (default: gitlab): ")
+	a.Contains(output.String(), "Invalid value 'BSD' for license")
+}
+
+func Test_templateManagerGatherConditionalParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with args that are only relevant in some cases
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: use_docker
+      description: Enable docker support
+      type: bool
+    - name: docker_registry
+      description: Docker registry
+      when: use_docker
+    - name: docker_tag
+      description: Docker tag
+      when: use_docker
+      default: latest`
+	configFile := path.Join(tmpDir, manifestFileName)
+	r.NoError(os.WriteFile(configFile, []byte(templateConfigText), 0600))
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+
+	// and user input which disables the conditional args
+	output := new(bytes.Buffer)
+	fakeInput := new(bytes.Buffer)
+	_, err = fakeInput.WriteString("no\n")
+	r.NoError(err)
+
+	cmd := cobra.Command{}
+	cmd.SetOut(output)
+	cmd.SetErr(output)
+	cmd.SetIn(fakeInput)
+
+	// when we process the user input
+	tm, err := New(options)
+	r.NoError(err)
+	err = tm.GatherParams(&cmd)
+	r.NoError(err)
+
+	// We expect the conditional args to be skipped
+	a.Equal(false, tm.templateContext["use_docker"])
+	a.NotContains(tm.templateContext, "docker_registry")
+	a.Equal("latest", tm.templateContext["docker_tag"])
+	a.NotContains(output.String(), "Docker registry")
+	a.NotContains(output.String(), "Docker tag")
+}
+
+func Test_templateManagerResolveConditionalParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with a required arg that is only relevant in some cases
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: ci
+      description: CI provider
+      choices: [none, github]
+      default: none
+    - name: ci_token
+      description: CI access token
+      when: ci != 'none'`
+	configFile := path.Join(tmpDir, manifestFileName)
+	r.NoError(os.WriteFile(configFile, []byte(templateConfigText), 0600))
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+	tm, err := New(options)
+	r.NoError(err)
+
+	// When the condition is not met, the required arg should not be reported as missing
+	r.NoError(tm.ResolveParams())
+	a.Equal("none", tm.templateContext["ci"])
+	a.NotContains(tm.templateContext, "ci_token")
+
+	// And when the condition is met, the required arg should be reported as missing
+	tm, err = New(options)
+	r.NoError(err)
+	r.NoError(tm.SetParams(map[string]string{"ci": "github"}))
+	r.ErrorIs(tm.ResolveParams(), e.NewMissingArgsError([]string{"ci_token"}))
+}
+
+func Test_templateManagerSetParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template with typed args
+	manifestText, err := os.ReadFile(getManifestFile("typed_manifest.yml"))
+	r.NoError(err)
+	r.NoError(os.WriteFile(path.Join(tmpDir, manifestFileName), manifestText, 0600))
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+	tm, err := New(options)
+	r.NoError(err)
+
+	// When we pre-populate some of the args
+	err = tm.SetParams(map[string]string{"use_docker": "yes"})
+	r.NoError(err)
+	a.Equal(true, tm.templateContext["use_docker"])
+
+	// Then invalid values and unknown args should be rejected
+	r.Error(tm.SetParams(map[string]string{"use_docker": "fubar"}))
+	r.Error(tm.SetParams(map[string]string{"does_not_exist": "value"}))
+}
+
+func Test_templateManagerResolveParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a template config file with a mix of required and default args
+	templateConfigText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      description: Name of the project
+    - name: package_name
+      description: Name of the package
+      default: "{{ project_name|lower }}"
+    - name: version
+      description: Project version`
+	configFile := path.Join(tmpDir, manifestFileName)
+	r.NoError(os.WriteFile(configFile, []byte(templateConfigText), 0600))
+
+	options := ao.TemplateOptions{
+		Source: tmpDir,
+		Name:   "MyName",
+		Type:   ao.TstLocal,
+	}
+	tm, err := New(options)
+	r.NoError(err)
+
+	// When we resolve the args without providing any values
+	err = tm.ResolveParams()
+
+	// Then all required args should be reported
+	r.ErrorIs(err, e.NewMissingArgsError([]string{"project_name", "version"}))
+
+	// And when the required args are provided, the defaults are computed
+	r.NoError(tm.SetParams(map[string]string{"project_name": "MyProj", "version": "1.0"}))
+	r.NoError(tm.ResolveParams())
+	a.Equal("myproj", tm.templateContext["package_name"])
+}
+
 func Test_templateManagerGenerate(t *testing.T) {
 	r := require.New(t)
 
@@ -270,7 +647,7 @@ func Test_templateManagerGenerate(t *testing.T) {
 			r.NoError(err)
 			err = tm.GatherParams(&cmd)
 			r.NoError(err)
-			err = tm.Generate(tmpDir)
+			err = tm.Generate(afero.NewOsFs(), tmpDir)
 			r.NoError(err)
 		})
 	}
@@ -313,7 +690,7 @@ func Test_templateManagerFailToGenerate(t *testing.T) {
 	r.NoError(err)
 
 	// When we try generating in a path that doesn't exist
-	err = tm.Generate(outputDir)
+	err = tm.Generate(afero.NewOsFs(), outputDir)
 
 	// The operation should fail
 	r.Error(err)
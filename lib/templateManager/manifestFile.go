@@ -1,6 +1,12 @@
 package templateManager
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -13,7 +19,7 @@ import (
 // VersionData stores version information about the template
 type VersionData struct {
 	// Schema version describing the format of the manifest file and its contents
-	Schema version.Version `yaml:"schema"`
+	Schema version.Version `yaml:"schema" required:"true"`
 	// Jigger the minimum version of the Rejigger app needed to process to the template
 	Jigger version.Version `yaml:"rejigger"`
 	// Template the version number associated with the current template
@@ -24,29 +30,96 @@ type VersionData struct {
 // args must be provided by the user to customize the content produced by the template
 type ArgData struct {
 	// Name of the argument, exactly as will be used in the template contents
-	Name string `yaml:"name"`
+	Name string `yaml:"name" required:"true"`
 	// Description descriptive text explaining the purpose of the argument
 	Description string `yaml:"description"`
-	// TODO: Consider adding default value here
+	// Type data type of the argument. Values provided by the user are validated against
+	// this type and stored in the template context using the equivalent native type
+	Type ArgType `yaml:"type"`
+	// Choices optional list of values the user must select from. Required when the
+	// Type is "enum"
+	Choices []string `yaml:"choices"`
+	// Default value to use when the user doesn't provide one. May be a literal value or a
+	// template expression referencing the values of previously defined args
+	Default string `yaml:"default"`
+	// When optional template expression evaluated against the values of previously defined
+	// args. The user is only prompted for this arg when the expression evaluates to true
+	When string `yaml:"when"`
 	// TODO: Consider having a short and long description, with the former limited to like 40 chars
 	// TODO: Consider having an optional flag here
-	// TODO: Consider having a data type identifier here to do some input validation on
+}
+
+// isChoice returns true if the given value is one of the choices defined for the arg
+func (a *ArgData) isChoice(value string) bool {
+	for _, curChoice := range a.Choices {
+		if curChoice == value {
+			return true
+		}
+	}
+	return false
+}
+
+// hasComputedDefault returns true if the default value for the arg contains template
+// expressions which need to be rendered before the value can be used
+func (a *ArgData) hasComputedDefault() bool {
+	return strings.Contains(a.Default, "{{") || strings.Contains(a.Default, "{%")
+}
+
+// FeatureData metadata describing an optional feature of the template. Features map sets of
+// files to a condition that controls whether they are included in the generated project
+type FeatureData struct {
+	// Name unique identifier for the feature
+	Name string `yaml:"name" required:"true"`
+	// Description descriptive text explaining the purpose of the feature
+	Description string `yaml:"description"`
+	// When template expression evaluated against the values of the template args. Files
+	// associated with the feature are only generated when the expression evaluates to true
+	When string `yaml:"when" required:"true"`
+	// Paths list of glob patterns, relative to the template root, matching the files and
+	// folders associated with this feature
+	Paths []string `yaml:"paths" required:"true"`
 }
 
 // TemplateData metadata describing the template being processed
 type TemplateData struct {
+	// Description optional descriptive text explaining the purpose of the template
+	Description string `yaml:"description"`
+	// Tags optional keywords describing the template
+	Tags []string `yaml:"tags"`
+	// Maintainer optional name or contact details for the person or team who maintains
+	// the template
+	Maintainer string `yaml:"maintainer"`
+	// Language optional name of the programming language used by projects generated
+	// from the template
+	Language string `yaml:"language"`
 	// Args list of input parameters supported by the template. These provide user configurable
 	// options that customize the content produced by the template
 	Args []ArgData `yaml:"args"`
-	// TODO: Consider adding 'Features' section for optional args, with mapping to specific files
-	// TODO: Consider adding a "Skip" section to list files that shouldn't be templated
-	// TODO: Consider adding an "Exclude" section to list files that should be ignored completely
+	// Features list of optional features supported by the template, which control which
+	// files get included in the generated project
+	Features []FeatureData `yaml:"features"`
+	// Raw list of glob patterns, relative to the template root, matching files whose contents
+	// should be copied verbatim without applying template args to them. The names of these
+	// files are still processed as templates
+	Raw []string `yaml:"raw"`
+	// Text list of glob patterns, relative to the template root, matching files which should
+	// always have template args applied to them. By default, files which appear to contain
+	// binary data are copied verbatim and these patterns allow that behavior to be overridden
+	Text []string `yaml:"text"`
+	// Exclusions list of regular expressions or gitignore-style globs (when prefixed with
+	// "glob:") matching files, relative to the template root, which should be excluded from
+	// the generated project. These are applied in addition to any exclusions defined in the
+	// application options
+	Exclusions []string `yaml:"exclusions"`
+	// Migrations list of steps needed to migrate projects generated from older versions of
+	// the template to newer versions, applied when a project is updated
+	Migrations []MigrationData `yaml:"migrations"`
 }
 
 // ManifestData parsed content of the manifest file associated with a template
 type ManifestData struct {
 	// Versions version identifiers for various aspects of the template
-	Versions VersionData `yaml:"versions"`
+	Versions VersionData `yaml:"versions" required:"true"`
 	// Template metadata describing the template
 	Template TemplateData `yaml:"template"`
 	// MiscParams all unparsed values in the manifest will be dumped into a simple map structure
@@ -56,6 +129,41 @@ type ManifestData struct {
 // -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 //									PARSING LOGIC
 
+// supportedSchemas constraint matching the versions of the manifest schema supported by
+// this version of the application
+const supportedSchemas = ">= 1.0, < 2.0"
+
+// checkCompatibility makes sure the running version of the application supports the
+// schema of the manifest and meets the minimum version required by the template
+func (v *VersionData) checkCompatibility() error {
+	if len(v.Schema.Original()) == 0 {
+		return e.NewManifestError([]string{"schema version is undefined"})
+	}
+	constraint, err := version.NewConstraint(supportedSchemas)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !constraint.Check(&v.Schema) {
+		return e.NewManifestError([]string{fmt.Sprintf(
+			"schema version %s is not supported, this version of Rejigger supports schema versions %s",
+			v.Schema.Original(), supportedSchemas)})
+	}
+
+	// Development builds can't be compared with release versions so we assume they
+	// support every template
+	if len(v.Jigger.Original()) == 0 || lib.Version == lib.DevVersion {
+		return nil
+	}
+	current, err := version.NewVersion(lib.Version)
+	if err != nil {
+		return errors.Wrap(err, "Invalid application version "+lib.Version)
+	}
+	if current.LessThan(&v.Jigger) {
+		return e.NewUnsupportedAppVersionError(v.Jigger.Original(), lib.Version)
+	}
+	return nil
+}
+
 // UnmarshalYAML custom YAML decoding method compatible with the YAML parsing library
 func (m *ManifestData) UnmarshalYAML(value *yaml.Node) error {
 	// Start by parsing version info from the manifest file
@@ -67,17 +175,22 @@ func (m *ManifestData) UnmarshalYAML(value *yaml.Node) error {
 	}
 	m.Versions = versionFields.Versions
 
-	// TODO: Validate the version identifier for the app here and fail if we are not
-	// 		 using a supported version
+	// Make sure we know how to process the rest of the manifest before we try
+	if err := m.Versions.checkCompatibility(); err != nil {
+		return err
+	}
 
 	// Then parse template metadata
 	var templateFields struct {
 		Template TemplateData `yaml:"template"`
 	}
-	if err := value.Decode(&templateFields); err != nil {
+	err := value.Decode(&templateFields)
+	// Values of the wrong type are skipped, so whatever could be decoded is kept to
+	// allow the rest of the template to be validated
+	m.Template = templateFields.Template
+	if err != nil {
 		return errors.WithStack(err)
 	}
-	m.Template = templateFields.Template
 
 	// Dump all remaining content into a simple map
 	var remaining map[string]interface{}
@@ -92,25 +205,154 @@ func (m *ManifestData) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// validate checks the contents of the parsed manifest to make sure they meet the
+// requirements for the application
+func (m *ManifestData) validate() error {
+	messages := m.problems()
+	if len(messages) == 0 {
+		return nil
+	}
+	return e.NewManifestError(messages)
+}
+
+// problems gets a description of every way the contents of the parsed manifest fail to
+// meet the requirements for the application
+func (m *ManifestData) problems() []string {
+	var messages []string
+	allNames := map[string]int{}
+	for i, curArg := range m.Template.Args {
+		if len(curArg.Name) == 0 {
+			messages = append(messages, fmt.Sprintf("arg %d name is undefined", i))
+		}
+		allNames[curArg.Name] += 1
+
+		if curArg.Type == AtUnknown {
+			messages = append(messages, fmt.Sprintf("arg %s type is not supported", curArg.Name))
+		}
+		if curArg.Type == AtEnum && len(curArg.Choices) == 0 {
+			messages = append(messages, fmt.Sprintf("arg %s is an enum but has no choices", curArg.Name))
+		}
+		if len(curArg.When) != 0 {
+			if _, err := compileCondition(curArg.When); err != nil {
+				messages = append(messages, fmt.Sprintf("arg %s has an invalid condition: %s", curArg.Name, err.Error()))
+			}
+		}
+		if curArg.Type != AtUnknown {
+			// Strip the choices so each one is validated against the arg type alone
+			choiceType := ArgData{Name: curArg.Name, Type: curArg.Type}
+			for _, curChoice := range curArg.Choices {
+				if _, err := parseArgValue(choiceType, curChoice); err != nil {
+					messages = append(messages, fmt.Sprintf("arg %s has an invalid choice: %s", curArg.Name, err.Error()))
+				}
+			}
+		}
+		if curArg.Type != AtUnknown && len(curArg.Default) != 0 && !curArg.hasComputedDefault() {
+			if _, err := parseArgValue(curArg, curArg.Default); err != nil {
+				messages = append(messages, fmt.Sprintf("arg %s has an invalid default: %s", curArg.Name, err.Error()))
+			}
+		}
+	}
+
+	// See if any arg names are duplicated
+	for name, count := range allNames {
+		if count > 1 {
+			messages = append(messages, fmt.Sprintf("there are %d args with the name %s", count, name))
+		}
+	}
+	messages = append(messages, m.validateFeatures()...)
+	messages = append(messages, m.validateMigrations()...)
+	for _, curPath := range m.Template.Raw {
+		if _, err := lib.CompileGlob(curPath); err != nil {
+			messages = append(messages, fmt.Sprintf("raw path is invalid: %s", err.Error()))
+		}
+	}
+	for _, curPath := range m.Template.Text {
+		if _, err := lib.CompileGlob(curPath); err != nil {
+			messages = append(messages, fmt.Sprintf("text path is invalid: %s", err.Error()))
+		}
+	}
+	for _, curExclusion := range m.Template.Exclusions {
+		if _, err := lib.CompileExclusion(curExclusion); err != nil {
+			messages = append(messages, fmt.Sprintf("exclusion %s is invalid", curExclusion))
+		}
+	}
+	return messages
+}
+
+// validateFeatures checks the feature definitions in the parsed manifest to make sure they
+// meet the requirements for the application
+func (m *ManifestData) validateFeatures() []string {
+	var retval []string
+	for i, curFeature := range m.Template.Features {
+		name := curFeature.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("%d", i)
+		}
+		if len(curFeature.When) == 0 {
+			retval = append(retval, fmt.Sprintf("feature %s condition is undefined", name))
+		} else if _, err := compileCondition(curFeature.When); err != nil {
+			retval = append(retval, fmt.Sprintf("feature %s has an invalid condition: %s", name, err.Error()))
+		}
+		if len(curFeature.Paths) == 0 {
+			retval = append(retval, fmt.Sprintf("feature %s has no paths", name))
+		}
+		for _, curPath := range curFeature.Paths {
+			if _, err := lib.CompileGlob(curPath); err != nil {
+				retval = append(retval, fmt.Sprintf("feature %s has an invalid path: %s", name, err.Error()))
+			}
+		}
+	}
+	return retval
+}
+
 // -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 //									PUBLIC INTERFACE
 
-// ParseManifest parses a template manifest file and returns a reference to
-// the parsed representation of the contents of the file
-func parseManifest(srcFS afero.Fs, path string) (ManifestData, error) {
+// ValidateManifest parses a template manifest file in strict mode, returning an error
+// describing every problem found in the file
+func ValidateManifest(srcFS afero.Fs, path string) error {
+	_, err := parseManifest(srcFS, path, true)
+	return err
+}
+
+// parseManifest parses a template manifest file and returns a reference to
+// the parsed representation of the contents of the file. In strict mode, unknown keys,
+// values of the wrong type and missing required keys are reported as errors instead
+// of being ignored
+func parseManifest(srcFS afero.Fs, path string, strict bool) (ManifestData, error) {
 	var retval ManifestData
 	buf, err := afero.ReadFile(srcFS, path)
 	if err != nil {
 		return retval, errors.WithStack(err)
 	}
 
-	// TODO: Find some way to get "Strict" mode to work properly (aka: KnownFields in v3)
+	if !strict {
+		err = yaml.Unmarshal(buf, &retval)
+		if err != nil {
+			return retval, errors.Wrap(err, "Error parsing template manifest: "+path)
+		}
+		return retval, retval.validate()
+	}
+
+	// yaml.v3 strict mode (aka: KnownFields) doesn't work with custom unmarshalers and
+	// stops at the first problem, so we check the document structure ourselves
 	//		https://github.com/go-yaml/yaml/issues/460
-	//		https://github.com/go-yaml/yaml/issues/642
-	err = yaml.Unmarshal(buf, &retval)
-	if err != nil {
+	var node yaml.Node
+	if err = yaml.Unmarshal(buf, &node); err != nil {
 		return retval, errors.Wrap(err, "Error parsing template manifest: "+path)
 	}
+	messages := lib.CheckYAMLStructure(path, &node, reflect.TypeOf(retval))
 
+	// Values of the wrong type have already been reported, and the rest of the manifest
+	// is still decoded so it can be validated as well
+	err = yaml.Unmarshal(buf, &retval)
+	var typeErr *yaml.TypeError
+	if err != nil && !(errors.As(err, &typeErr) && len(messages) != 0) {
+		return retval, errors.Wrap(err, "Error parsing template manifest: "+path)
+	}
+	messages = append(messages, retval.problems()...)
+	if len(messages) != 0 {
+		return retval, e.NewManifestError(messages)
+	}
 	return retval, nil
 }
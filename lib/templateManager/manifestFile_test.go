@@ -5,6 +5,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
 	"github.com/hashicorp/go-version"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -20,7 +22,7 @@ func Test_parseManifest(t *testing.T) {
 	srcFile := getManifestFile("simple_manifest.yml")
 
 	// When we parse it
-	manifest, err := parseManifest(afero.NewOsFs(), srcFile)
+	manifest, err := parseManifest(afero.NewOsFs(), srcFile, false)
 
 	// We expect no errors
 	r.NoError(err)
@@ -60,7 +62,7 @@ func Test_parseManifestNotExist(t *testing.T) {
 	r.NoError(err)
 	defer os.RemoveAll(tmpDir)
 
-	_, err = parseManifest(afero.NewOsFs(), path.Join(tmpDir, "fubar.yml"))
+	_, err = parseManifest(afero.NewOsFs(), path.Join(tmpDir, "fubar.yml"), false)
 	a.Error(err)
 }
 
@@ -82,7 +84,7 @@ func Test_parseManifestInvalidYAML(t *testing.T) {
 	r.NoError(srcfile.Close())
 
 	// when we try to parse the file
-	_, err = parseManifest(afero.NewOsFs(), samplefile)
+	_, err = parseManifest(afero.NewOsFs(), samplefile, false)
 
 	// then we expect error results
 	emptyTypeErr := yaml.TypeError{}
@@ -94,9 +96,232 @@ func Test_parseManifestInvalidTemplateArgs(t *testing.T) {
 
 	srcFile := getManifestFile("simple_manifest_with_invalid_args.yml")
 
-	_, err := parseManifest(afero.NewOsFs(), srcFile)
+	_, err := parseManifest(afero.NewOsFs(), srcFile, false)
 	// TODO: Find some way to make error reporting here more user friendly
 	//		 may require a different YAML parsing library
 	// https://github.com/go-yaml/yaml/pull/901
 	a.Error(err)
 }
+
+func Test_parseManifestTypedArgs(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a manifest file with typed args
+	srcFile := getManifestFile("typed_manifest.yml")
+
+	// When we parse it
+	manifest, err := parseManifest(afero.NewOsFs(), srcFile, false)
+	r.NoError(err)
+
+	// Then the arg types should be parsed correctly
+	r.Equal(3, len(manifest.Template.Args))
+	a.Equal(AtUndefined, manifest.Template.Args[0].Type)
+	a.Equal(AtBool, manifest.Template.Args[1].Type)
+	a.Equal(AtEnum, manifest.Template.Args[2].Type)
+	a.Equal([]string{"MIT", "GPL"}, manifest.Template.Args[2].Choices)
+}
+
+func Test_parseManifestValidationErrors(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a manifest file containing several invalid arg definitions
+	samplefile := path.Join(tmpDir, "fubar.yml")
+	manifestText := `
+versions:
+  schema: 1.0
+  rejigger: 0.0.1
+  template: 0.5
+template:
+  args:
+    - name: first
+      type: fubar
+    - name: second
+      type: enum
+    - description: no name
+    - name: third
+      type: int
+      default: fubar
+    - name: fourth
+      type: int
+      default: "{{ first }}"
+    - name: fifth
+      type: int
+      choices: [1, two]
+      default: 3
+    - name: sixth
+      when: "first !="
+  features:
+    - name: ci
+      paths: ["file[.txt"]
+    - name: docs
+      when: first
+  raw:
+    - "[.github"
+  text:
+    - "[.png"
+  exclusions:
+    - "glob:[docs"
+  migrations:
+    - version: 1.0
+      renames:
+        - from: old.txt
+        - from: /etc/passwd
+          to: passwd
+      deletions:
+        - ../..
+      args:
+        unknown: value
+        third: fubar
+      hooks:
+        - "{% if %}"
+    - version: 1.0
+    - description: Missing version
+`
+	r.NoError(os.WriteFile(samplefile, []byte(manifestText), 0600))
+
+	// when we try to parse the file
+	_, err = parseManifest(afero.NewOsFs(), samplefile, false)
+
+	// then every problem should be reported
+	r.Error(err)
+	a.Contains(err.Error(), "arg first type is not supported")
+	a.Contains(err.Error(), "arg second is an enum but has no choices")
+	a.Contains(err.Error(), "arg 2 name is undefined")
+	a.Contains(err.Error(), "arg third has an invalid default")
+	a.NotContains(err.Error(), "arg fourth")
+	a.Contains(err.Error(), "arg fifth has an invalid choice: Invalid value 'two'")
+	a.Contains(err.Error(), "arg fifth has an invalid default: Invalid value '3'")
+	a.Contains(err.Error(), "arg sixth has an invalid condition")
+	a.Contains(err.Error(), "feature ci condition is undefined")
+	a.Contains(err.Error(), "feature ci has an invalid path")
+	a.Contains(err.Error(), "feature docs has no paths")
+	a.Contains(err.Error(), "raw path is invalid")
+	a.Contains(err.Error(), "text path is invalid")
+	a.Contains(err.Error(), "exclusion glob:[docs is invalid")
+	a.Contains(err.Error(), "migration 1.0.0 is newer than the template version 0.5")
+	a.Contains(err.Error(), "migration 1.0.0 has a rename without a source or target path")
+	a.Contains(err.Error(), "migration 1.0.0 renames a path outside the project: /etc/passwd")
+	a.Contains(err.Error(), "migration 1.0.0 deletes a path outside the project: ../..")
+	a.Contains(err.Error(), "migration 1.0.0 sets value for unknown arg unknown")
+	a.Contains(err.Error(), "migration 1.0.0 has an invalid value: Invalid value 'fubar'")
+	a.Contains(err.Error(), "migration 1.0.0 has an invalid hook")
+	a.Contains(err.Error(), "there are 2 migrations for version 1.0.0")
+	a.Contains(err.Error(), "migration 2 version is undefined")
+}
+
+func Test_parseManifestVersionCompatibility(t *testing.T) {
+	r := require.New(t)
+
+	tests := map[string]struct {
+		versions   string
+		appVersion string
+		expError   error
+	}{
+		"Supported versions": {
+			versions:   "schema: 1.2\n  rejigger: 1.0.0",
+			appVersion: "1.0.0",
+		},
+		"Development build": {
+			versions:   "schema: 1.0\n  rejigger: 9.0.0",
+			appVersion: lib.DevVersion,
+		},
+		"No minimum app version": {
+			versions:   "schema: 1.0",
+			appVersion: "1.0.0",
+		},
+		"Newer app version required": {
+			versions:   "schema: 1.0\n  rejigger: 1.2.0",
+			appVersion: "1.0.0",
+			expError:   e.NewUnsupportedAppVersionError("1.2.0", "1.0.0"),
+		},
+		"Unsupported schema": {
+			versions:   "schema: 2.0\n  rejigger: 1.0.0",
+			appVersion: "1.0.0",
+			expError: e.NewManifestError([]string{
+				"schema version 2.0 is not supported, this version of Rejigger supports schema versions " + supportedSchemas}),
+		},
+		"Missing schema": {
+			versions:   "rejigger: 1.0.0",
+			appVersion: "1.0.0",
+			expError:   e.NewManifestError([]string{"schema version is undefined"}),
+		},
+	}
+
+	originalVersion := lib.Version
+	defer func() { lib.Version = originalVersion }()
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a manifest file requiring specific versions
+			srcFS := afero.NewMemMapFs()
+			manifestText := "versions:\n  " + data.versions + "\n  template: 1.0\n"
+			r.NoError(afero.WriteFile(srcFS, "/.rejig.yml", []byte(manifestText), 0644))
+
+			// When we parse it with a specific version of the app
+			lib.Version = data.appVersion
+			_, err := parseManifest(srcFS, "/.rejig.yml", false)
+
+			// Then incompatible manifests should be rejected
+			if data.expError == nil {
+				r.NoError(err)
+			} else {
+				r.ErrorIs(err, data.expError)
+			}
+		})
+	}
+}
+
+func Test_ValidateManifest(t *testing.T) {
+	r := require.New(t)
+
+	// Given a manifest file containing several typos
+	srcFS := afero.NewMemMapFs()
+	manifestText := `versions:
+  schema: 1.0
+  template: 1.0
+template:
+  args:
+    - name: project_name
+      descripton: Name of the project
+    - description: Missing name
+  features:
+    - name: docs
+      when: true
+      paths: docs/**
+misc: fubar
+`
+	r.NoError(afero.WriteFile(srcFS, "/.rejig.yml", []byte(manifestText), 0644))
+
+	// When we validate the manifest
+	err := ValidateManifest(srcFS, "/.rejig.yml")
+
+	// Then every problem should be reported in a single error, along with its location
+	// when it is known
+	r.ErrorIs(err, e.NewManifestError([]string{
+		"/.rejig.yml:7:7: unknown key template.args[0].descripton",
+		"/.rejig.yml:8:7: missing required key template.args[1].name",
+		"/.rejig.yml:12:14: expected a list for template.features[0].paths",
+		"/.rejig.yml:13:1: unknown key misc",
+		"arg 1 name is undefined",
+		"feature docs has no paths",
+	}))
+}
+
+func Test_ValidateManifestSample(t *testing.T) {
+	r := require.New(t)
+
+	// Given the manifest file for a sample template
+	srcFile := path.Join(getProjectDir(), manifestFileName)
+
+	// When we validate the manifest
+	err := ValidateManifest(afero.NewOsFs(), srcFile)
+
+	// Then no problems should be found
+	r.NoError(err)
+}
@@ -0,0 +1,108 @@
+package templateManager
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// conflictStart marker placed before the project's version of a conflicting block of lines
+	conflictStart = "<<<<<<< project\n"
+	// conflictSeparator marker placed between the two versions of a conflicting block of lines
+	conflictSeparator = "=======\n"
+	// conflictEnd marker placed after the template's version of a conflicting block of lines
+	conflictEnd = ">>>>>>> template\n"
+)
+
+// mergeText performs a three-way merge of the changes made to some base content by the
+// project (ours) and by the template (theirs). Changes made to different lines are combined
+// automatically. Lines changed differently by both are surrounded by conflict markers, in
+// which case the returned flag is false
+func mergeText(base string, ours string, theirs string) (string, bool) {
+	baseLines := splitLines(base)
+	ourLines := splitLines(ours)
+	theirLines := splitLines(theirs)
+	ourMatches := matchLines(baseLines, ourLines)
+	theirMatches := matchLines(baseLines, theirLines)
+
+	var retval strings.Builder
+	clean := true
+	iBase, iOurs, iTheirs := 0, 0, 0
+	for {
+		// Copy lines which are unchanged by both sides
+		for iBase < len(baseLines) && ourMatches[iBase] == iOurs && theirMatches[iBase] == iTheirs {
+			retval.WriteString(baseLines[iBase])
+			iBase++
+			iOurs++
+			iTheirs++
+		}
+
+		// Find the next line which is unchanged by both sides, marking the end of the
+		// block of lines changed by at least one of them
+		endBase := iBase
+		for endBase < len(baseLines) && (ourMatches[endBase] < iOurs || theirMatches[endBase] < iTheirs) {
+			endBase++
+		}
+		endOurs, endTheirs := len(ourLines), len(theirLines)
+		if endBase < len(baseLines) {
+			endOurs, endTheirs = ourMatches[endBase], theirMatches[endBase]
+		}
+
+		baseBlock := strings.Join(baseLines[iBase:endBase], "")
+		ourBlock := strings.Join(ourLines[iOurs:endOurs], "")
+		theirBlock := strings.Join(theirLines[iTheirs:endTheirs], "")
+		switch {
+		case ourBlock == baseBlock:
+			retval.WriteString(theirBlock)
+		case theirBlock == baseBlock || theirBlock == ourBlock:
+			retval.WriteString(ourBlock)
+		default:
+			clean = false
+			retval.WriteString(conflictStart)
+			retval.WriteString(terminateLine(ourBlock))
+			retval.WriteString(conflictSeparator)
+			retval.WriteString(terminateLine(theirBlock))
+			retval.WriteString(conflictEnd)
+		}
+
+		if endBase >= len(baseLines) {
+			break
+		}
+		iBase, iOurs, iTheirs = endBase, endOurs, endTheirs
+	}
+	return retval.String(), clean
+}
+
+// splitLines breaks text into lines, preserving the line endings
+func splitLines(text string) []string {
+	retval := strings.SplitAfter(text, "\n")
+	if retval[len(retval)-1] == "" {
+		retval = retval[:len(retval)-1]
+	}
+	return retval
+}
+
+// matchLines maps the index of every line of the base content to the index of the
+// same line in the modified content, or -1 if the line was changed or removed
+func matchLines(base []string, modified []string) []int {
+	retval := make([]int, len(base))
+	for i := range retval {
+		retval[i] = -1
+	}
+	matcher := difflib.NewMatcherWithJunk(base, modified, false, nil)
+	for _, curBlock := range matcher.GetMatchingBlocks() {
+		for i := 0; i < curBlock.Size; i++ {
+			retval[curBlock.A+i] = curBlock.B + i
+		}
+	}
+	return retval
+}
+
+// terminateLine makes sure a non-empty block of text ends with a line ending
+func terminateLine(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package templateManager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeText(t *testing.T) {
+	r := require.New(t)
+
+	base := "one\ntwo\nthree\nfour\nfive\n"
+	tests := map[string]struct {
+		ours     string
+		theirs   string
+		expected string
+	}{
+		"No changes": {
+			ours:     base,
+			theirs:   base,
+			expected: base,
+		},
+		"Project changes only": {
+			ours:     "one\nTWO\nthree\nfour\nfive\n",
+			theirs:   base,
+			expected: "one\nTWO\nthree\nfour\nfive\n",
+		},
+		"Template changes only": {
+			ours:     base,
+			theirs:   "one\ntwo\nthree\nFOUR\nfive\n",
+			expected: "one\ntwo\nthree\nFOUR\nfive\n",
+		},
+		"Changes to different lines": {
+			ours:     "one\nTWO\nthree\nfour\nfive\n",
+			theirs:   "one\ntwo\nthree\nFOUR\nfive\n",
+			expected: "one\nTWO\nthree\nFOUR\nfive\n",
+		},
+		"Identical changes": {
+			ours:     "one\nTWO\nthree\nfour\nfive\n",
+			theirs:   "one\nTWO\nthree\nfour\nfive\n",
+			expected: "one\nTWO\nthree\nfour\nfive\n",
+		},
+		"Insertions and deletions": {
+			ours:     "zero\none\ntwo\nthree\nfour\nfive\n",
+			theirs:   "one\ntwo\nfour\nfive\nsix\n",
+			expected: "zero\none\ntwo\nfour\nfive\nsix\n",
+		},
+		"Project removes all lines": {
+			ours:     "",
+			theirs:   base,
+			expected: "",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, clean := mergeText(base, data.ours, data.theirs)
+			r.True(clean)
+			r.Equal(data.expected, result)
+		})
+	}
+}
+
+func Test_mergeTextEmptyBase(t *testing.T) {
+	r := require.New(t)
+
+	result, clean := mergeText("", "", "one\ntwo\n")
+	r.True(clean)
+	r.Equal("one\ntwo\n", result)
+}
+
+func Test_mergeTextConflict(t *testing.T) {
+	r := require.New(t)
+
+	// Given changes made to the same line by the project and the template
+	base := "one\ntwo\nthree"
+	ours := "one\nTWO\nthree"
+	theirs := "one\nDeux\nthree"
+
+	// When we merge the changes
+	result, clean := mergeText(base, ours, theirs)
+
+	// Then the conflicting lines should be surrounded by conflict markers
+	r.False(clean)
+	r.Equal("one\n<<<<<<< project\nTWO\n=======\nDeux\n>>>>>>> template\nthree", result)
+}
+
+func Test_mergeTextConflictNoTrailingNewline(t *testing.T) {
+	r := require.New(t)
+
+	result, clean := mergeText("one", "two", "three")
+	r.False(clean)
+	r.Equal("<<<<<<< project\ntwo\n=======\nthree\n>>>>>>> template\n", result)
+}
@@ -0,0 +1,339 @@
+package templateManager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	e "github.com/TheFriendlyCoder/rejigger/lib/errors"
+	"github.com/flosch/pongo2/v6"
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// RenameData describes a file which has been moved to a new location in the template
+type RenameData struct {
+	// From path of the file, relative to the project root, before the migration
+	From string `yaml:"from" required:"true"`
+	// To path of the file, relative to the project root, after the migration
+	To string `yaml:"to" required:"true"`
+}
+
+// MigrationData describes the steps needed to migrate a project generated from an older
+// version of a template to a specific version of the template
+type MigrationData struct {
+	// Version the version of the template these migration steps upgrade projects to
+	Version version.Version `yaml:"version" required:"true"`
+	// Description descriptive text explaining the changes made by this version
+	Description string `yaml:"description"`
+	// Renames files which have been moved, and are moved in the project before the changes
+	// made to the template are merged into it, so changes made to them are preserved
+	Renames []RenameData `yaml:"renames"`
+	// Deletions paths of files, relative to the project root, which are removed from the
+	// project even if they have been modified since the project was generated
+	Deletions []string `yaml:"deletions"`
+	// Args values for args introduced in this version of the template, used for projects
+	// migrated from older versions instead of prompting the user for them
+	Args map[string]string `yaml:"args"`
+	// Hooks shell commands run from the project root once the project has been updated.
+	// Template args may be referenced in the commands
+	Hooks []string `yaml:"hooks"`
+}
+
+// validateMigrations checks the migration definitions in the parsed manifest to make sure
+// they meet the requirements for the application
+func (m *ManifestData) validateMigrations() []string {
+	var retval []string
+	allVersions := map[string]int{}
+	for i, curMigration := range m.Template.Migrations {
+		if len(curMigration.Version.Original()) == 0 {
+			retval = append(retval, fmt.Sprintf("migration %d version is undefined", i))
+			continue
+		}
+		name := curMigration.Version.String()
+		allVersions[name] += 1
+		if curMigration.Version.GreaterThan(&m.Versions.Template) {
+			retval = append(retval, fmt.Sprintf("migration %s is newer than the template version %s",
+				name, m.Versions.Template.String()))
+		}
+		for _, curRename := range curMigration.Renames {
+			if len(curRename.From) == 0 || len(curRename.To) == 0 {
+				retval = append(retval, fmt.Sprintf("migration %s has a rename without a source or target path", name))
+				continue
+			}
+			for _, curPath := range []string{curRename.From, curRename.To} {
+				if !isProjectPath(curPath) {
+					retval = append(retval, fmt.Sprintf("migration %s renames a path outside the project: %s", name, curPath))
+				}
+			}
+		}
+		for _, curDeletion := range curMigration.Deletions {
+			if !isProjectPath(curDeletion) {
+				retval = append(retval, fmt.Sprintf("migration %s deletes a path outside the project: %s", name, curDeletion))
+			}
+		}
+		for argName, value := range curMigration.Args {
+			arg := m.findArg(argName)
+			if arg == nil {
+				retval = append(retval, fmt.Sprintf("migration %s sets value for unknown arg %s", name, argName))
+				continue
+			}
+			if _, err := parseArgValue(*arg, value); err != nil {
+				retval = append(retval, fmt.Sprintf("migration %s has an invalid value: %s", name, err.Error()))
+			}
+		}
+		for _, curHook := range curMigration.Hooks {
+			if _, err := pongo2.FromString(curHook); err != nil {
+				retval = append(retval, fmt.Sprintf("migration %s has an invalid hook: %s", name, err.Error()))
+			}
+		}
+	}
+	for name, count := range allVersions {
+		if count > 1 {
+			retval = append(retval, fmt.Sprintf("there are %d migrations for version %s", count, name))
+		}
+	}
+	return retval
+}
+
+// isProjectPath returns true if a path from a migration is relative to the project root
+// and refers to something inside the project, rather than the root itself or anything
+// outside of it
+func isProjectPath(relPath string) bool {
+	cleaned := filepath.Clean(filepath.FromSlash(relPath))
+	return filepath.IsLocal(cleaned) && cleaned != "."
+}
+
+// projectFile gets the full path to a file referenced by a migration, making sure it
+// doesn't point outside the project in rootDir
+func projectFile(rootDir string, relPath string) (string, error) {
+	retval := filepath.Clean(filepath.Join(rootDir, filepath.FromSlash(relPath)))
+	rel, err := filepath.Rel(rootDir, retval)
+	if err != nil || !isProjectPath(rel) {
+		return "", e.NewSimpleError("Unable to migrate project, path is outside the project: " + relPath)
+	}
+	return retval, nil
+}
+
+// findArg locates the definition for an arg declared in the manifest
+// Returns nil if the manifest has no arg with the given name
+func (m *ManifestData) findArg(name string) *ArgData {
+	for i := range m.Template.Args {
+		if m.Template.Args[i].Name == name {
+			return &m.Template.Args[i]
+		}
+	}
+	return nil
+}
+
+// GetMigrations gets the migration steps needed to move a project generated from the given
+// version of the template to the version being managed, ordered from oldest to newest.
+// Projects generated from templates without a version receive every migration
+func (t *templateManager) GetMigrations(fromVersion string) ([]MigrationData, error) {
+	if len(t.manifestData.Template.Migrations) == 0 {
+		return nil, nil
+	}
+	var from *version.Version
+	if len(fromVersion) != 0 {
+		var err error
+		from, err = version.NewVersion(fromVersion)
+		if err != nil {
+			return nil, e.NewSimpleError(fmt.Sprintf(
+				"Unable to migrate project, invalid template version %s: %s", fromVersion, err.Error()))
+		}
+	}
+
+	var retval []MigrationData
+	for _, curMigration := range t.manifestData.Template.Migrations {
+		if (from == nil || curMigration.Version.GreaterThan(from)) &&
+			curMigration.Version.LessThanOrEqual(&t.manifestData.Versions.Template) {
+			retval = append(retval, curMigration)
+		}
+	}
+	sort.Slice(retval, func(i, j int) bool {
+		return retval[i].Version.LessThan(&retval[j].Version)
+	})
+	return retval, nil
+}
+
+// SetMigrationParams pre-populates values for user defined options introduced by a set of
+// migrations, for any options which have not already been provided
+func (t *templateManager) SetMigrationParams(migrations []MigrationData) error {
+	values := map[string]string{}
+	for _, curMigration := range migrations {
+		for name, value := range curMigration.Args {
+			if _, ok := t.templateContext[name]; !ok {
+				values[name] = value
+			}
+		}
+	}
+	return t.SetParams(values)
+}
+
+// ApplyMigrations moves and removes project files as described by a set of migrations.
+// Changes are applied to both the project found in rootDir of targetFS, and to the output
+// of the original version of the template found in rootDir of baseFS, so that subsequent
+// template changes can be merged into the files at their new locations
+func ApplyMigrations(out io.Writer, migrations []MigrationData, baseFS afero.Fs, targetFS afero.Fs, rootDir string) error {
+	for _, curMigration := range migrations {
+		lib.SNF(fmt.Fprintf(out, "Applying migration %s...\n", curMigration.Version.String()))
+		for _, curRename := range curMigration.Renames {
+			from, err := projectFile(rootDir, curRename.From)
+			if err != nil {
+				return err
+			}
+			to, err := projectFile(rootDir, curRename.To)
+			if err != nil {
+				return err
+			}
+			lib.SNF(fmt.Fprintf(out, "Renaming %s to %s\n", curRename.From, curRename.To))
+			if err := renameFile(baseFS, from, to); err != nil {
+				return err
+			}
+			if err := renameFile(targetFS, from, to); err != nil {
+				return err
+			}
+		}
+		for _, curDeletion := range curMigration.Deletions {
+			path, err := projectFile(rootDir, curDeletion)
+			if err != nil {
+				return err
+			}
+			lib.SNF(fmt.Fprintf(out, "Removing %s\n", curDeletion))
+			if err := baseFS.RemoveAll(path); err != nil {
+				return errors.WithStack(err)
+			}
+			if err := targetFS.RemoveAll(path); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// renameFile moves a file to a new location, if it exists, creating the parent folder of
+// the new location as needed
+func renameFile(srcFS afero.Fs, from string, to string) error {
+	exists, err := afero.Exists(srcFS, from)
+	if err != nil || !exists {
+		return errors.WithStack(err)
+	}
+	if err = srcFS.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrap(srcFS.Rename(from, to), "Failed to rename "+from)
+}
+
+// RenderMigrationHooks gets the shell commands defined by the hooks of a set of migrations,
+// with the values of template args substituted into them. Arg values are quoted so they
+// are passed to the shell as-is, regardless of the characters they contain
+func (t *templateManager) RenderMigrationHooks(migrations []MigrationData) ([]string, error) {
+	context := pongo2.Context{}
+	for name, value := range t.templateContext {
+		context[name] = quoteHookValue(value)
+	}
+
+	var retval []string
+	for _, curMigration := range migrations {
+		for _, curHook := range curMigration.Hooks {
+			tpl, err := pongo2.FromString(curHook)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			command, err := tpl.Execute(context)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to render hook: "+curHook)
+			}
+			retval = append(retval, command)
+		}
+	}
+	return retval, nil
+}
+
+// quoteHookValue quotes the value of a template arg so it can be safely used in a shell
+// command. Numbers and booleans can't contain special characters so they are left as-is.
+// Quoted values are marked as safe so they aren't HTML escaped when rendered
+func quoteHookValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return pongo2.AsSafeValue(shellQuote(v))
+	case []string:
+		retval := make([]*pongo2.Value, 0, len(v))
+		for _, curItem := range v {
+			retval = append(retval, pongo2.AsSafeValue(shellQuote(curItem)))
+		}
+		return retval
+	case fmt.Stringer:
+		return pongo2.AsSafeValue(shellQuote(v.String()))
+	default:
+		return value
+	}
+}
+
+// shellQuote quotes a character string so it is treated as a single literal argument by
+// the shell used to run hooks
+func shellQuote(value string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// ConfirmMigrationHooks asks the user whether the shell commands defined by template
+// migrations should be run
+func (t *templateManager) ConfirmMigrationHooks(cmd *cobra.Command) (bool, error) {
+	reader := t.getReader(cmd)
+	for {
+		lib.SNF(fmt.Fprint(cmd.OutOrStdout(), "Run these commands? [y]es/[n]o (default: n): "))
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		default:
+			lib.SNF(fmt.Fprintln(cmd.OutOrStdout(), "Invalid selection: "+strings.TrimSpace(value)))
+		}
+	}
+}
+
+// RunMigrationHooks runs the shell commands defined by template migrations from the root
+// folder of a project, stopping at the first command that fails
+func RunMigrationHooks(out io.Writer, commands []string, rootDir string) error {
+	for _, curCommand := range commands {
+		lib.SNF(fmt.Fprintf(out, "Running %s\n", curCommand))
+		if err := runHook(out, curCommand, rootDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runHook runs a shell command from the given folder, sending its output to out
+func runHook(out io.Writer, command string, workingDir string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Dir = workingDir
+	cmd.Env = os.Environ()
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "Migration hook failed: "+command)
+	}
+	return nil
+}
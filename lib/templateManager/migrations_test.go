@@ -0,0 +1,270 @@
+package templateManager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMigration creates migration steps for a specific template version
+func newMigration(r *require.Assertions, ver string) MigrationData {
+	parsed, err := version.NewVersion(ver)
+	r.NoError(err)
+	return MigrationData{Version: *parsed}
+}
+
+// newMigratingTemplate creates a template manager for version 3.0 of a template which
+// defines migrations for several earlier versions
+func newMigratingTemplate(r *require.Assertions) templateManager {
+	current, err := version.NewVersion("3.0")
+	r.NoError(err)
+	migration3 := newMigration(r, "3.0")
+	migration3.Args = map[string]string{"license": "GPL"}
+	migration1 := newMigration(r, "1.5")
+	migration2 := newMigration(r, "2.0")
+	migration2.Args = map[string]string{"license": "MIT", "ci": "none"}
+	return templateManager{
+		manifestData: ManifestData{
+			Versions: VersionData{Template: *current},
+			Template: TemplateData{
+				Args: []ArgData{
+					{Name: "license"},
+					{Name: "ci"},
+				},
+				Migrations: []MigrationData{migration3, migration1, migration2},
+			},
+		},
+		templateContext: map[string]any{},
+	}
+}
+
+func Test_GetMigrations(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		fromVersion string
+		expected    []string
+	}{
+		"All migrations": {
+			fromVersion: "1.0",
+			expected:    []string{"1.5.0", "2.0.0", "3.0.0"},
+		},
+		"Some migrations": {
+			fromVersion: "1.5",
+			expected:    []string{"2.0.0", "3.0.0"},
+		},
+		"No migrations": {
+			fromVersion: "3.0",
+			expected:    nil,
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a template which defines several migrations
+			tm := newMigratingTemplate(r)
+
+			// When we look up the migrations needed for a project
+			migrations, err := tm.GetMigrations(data.fromVersion)
+			r.NoError(err)
+
+			// Then the relevant migrations should be returned in order
+			var actual []string
+			for _, curMigration := range migrations {
+				actual = append(actual, curMigration.Version.String())
+			}
+			a.Equal(data.expected, actual)
+		})
+	}
+}
+
+func Test_GetMigrationsInvalidVersion(t *testing.T) {
+	r := require.New(t)
+
+	tm := newMigratingTemplate(r)
+	_, err := tm.GetMigrations("fubar")
+	r.Error(err)
+	r.Contains(err.Error(), "fubar")
+}
+
+func Test_SetMigrationParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with several migrations
+	tm := newMigratingTemplate(r)
+	migrations, err := tm.GetMigrations("1.0")
+	r.NoError(err)
+
+	// and a value already provided for one of the args they define
+	r.NoError(tm.SetParams(map[string]string{"ci": "github"}))
+
+	// When we apply the migration values
+	r.NoError(tm.SetMigrationParams(migrations))
+
+	// Then the most recent migration value should be used for the other arg
+	a.Equal("GPL", tm.templateContext["license"])
+	a.Equal("github", tm.templateContext["ci"])
+}
+
+func Test_ApplyMigrations(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given migrations which rename and delete files
+	migration := newMigration(r, "2.0")
+	migration.Renames = []RenameData{
+		{From: "old.txt", To: "sub/new.txt"},
+		{From: "missing.txt", To: "other.txt"},
+	}
+	migration.Deletions = []string{"obsolete.txt"}
+
+	// and a project and template output which contain those files
+	baseFS := newFileSystem(r, map[string]string{
+		"old.txt":      "original",
+		"obsolete.txt": "obsolete",
+	})
+	targetFS := newFileSystem(r, map[string]string{
+		"old.txt":      "modified",
+		"obsolete.txt": "modified",
+	})
+
+	// When we apply the migrations
+	output := new(bytes.Buffer)
+	r.NoError(ApplyMigrations(output, []MigrationData{migration}, baseFS, targetFS, "/proj"))
+
+	// Then the files should be moved and removed in both the project and template output
+	for srcFS, expected := range map[afero.Fs]string{baseFS: "original", targetFS: "modified"} {
+		contents, err := afero.ReadFile(srcFS, "/proj/sub/new.txt")
+		r.NoError(err)
+		a.Equal(expected, string(contents))
+		for _, curFile := range []string{"/proj/old.txt", "/proj/obsolete.txt", "/proj/other.txt"} {
+			exists, err := afero.Exists(srcFS, curFile)
+			r.NoError(err)
+			a.False(exists, curFile)
+		}
+	}
+	a.Contains(output.String(), "Applying migration 2.0.0")
+}
+
+func Test_ApplyMigrationsOutsideProject(t *testing.T) {
+	tests := map[string]struct {
+		renames   []RenameData
+		deletions []string
+	}{
+		"Deletion climbing out of the project": {
+			deletions: []string{"../outside.txt"},
+		},
+		"Deletion of the project root": {
+			deletions: []string{"sub/.."},
+		},
+		"Rename source outside the project": {
+			renames: []RenameData{{From: "../outside.txt", To: "inside.txt"}},
+		},
+		"Rename target outside the project": {
+			renames: []RenameData{{From: "inside.txt", To: "../../outside.txt"}},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			a := assert.New(t)
+
+			// Given a migration referencing paths outside the project
+			migration := newMigration(r, "2.0")
+			migration.Renames = data.renames
+			migration.Deletions = data.deletions
+
+			// and file systems containing files inside and outside the project
+			files := map[string]string{"inside.txt": "inside"}
+			baseFS := newFileSystem(r, files)
+			targetFS := newFileSystem(r, files)
+			r.NoError(afero.WriteFile(targetFS, "/outside.txt", []byte("outside"), 0600))
+
+			// When we apply the migration
+			err := ApplyMigrations(new(bytes.Buffer), []MigrationData{migration}, baseFS, targetFS, "/proj")
+
+			// Then it should be rejected
+			r.Error(err)
+			a.Contains(err.Error(), "path is outside the project")
+
+			// and no files should be changed
+			for _, curFile := range []string{"/outside.txt", "/proj/inside.txt"} {
+				exists, err := afero.Exists(targetFS, curFile)
+				r.NoError(err)
+				a.True(exists, curFile)
+			}
+		})
+	}
+}
+
+func Test_RenderMigrationHooks(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a migration with hooks referencing template args
+	migration := newMigration(r, "2.0")
+	migration.Hooks = []string{"echo {{ license }}", "touch {% for f in files %}{{ f }} {% endfor %}"}
+	tm := newMigratingTemplate(r)
+	tm.manifestData.Template.Args = append(tm.manifestData.Template.Args, ArgData{Name: "files", Type: AtList})
+	r.NoError(tm.SetParams(map[string]string{"license": "it's; rm -rf /", "files": "a b,c"}))
+
+	// When we render the hooks
+	commands, err := tm.RenderMigrationHooks([]MigrationData{migration})
+
+	// Then the arg values should be quoted so they are passed to the shell as-is
+	r.NoError(err)
+	if runtime.GOOS == "windows" {
+		a.Equal([]string{`echo "it's; rm -rf /"`, `touch "a b" "c" `}, commands)
+	} else {
+		a.Equal([]string{`echo 'it'\''s; rm -rf /'`, `touch 'a b' 'c' `}, commands)
+	}
+}
+
+func Test_RunMigrationHooks(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a hook which writes to a file
+	tmpDir := t.TempDir()
+
+	// When we run the hook
+	output := new(bytes.Buffer)
+	r.NoError(RunMigrationHooks(output, []string{"echo MIT> hook.txt"}, tmpDir))
+
+	// Then the command should be run from the project folder
+	a.Contains(output.String(), "Running echo MIT> hook.txt")
+	contents, err := os.ReadFile(filepath.Join(tmpDir, "hook.txt"))
+	r.NoError(err)
+	a.Contains(string(contents), "MIT")
+
+	// And failing hooks should be reported
+	err = RunMigrationHooks(output, []string{"exit 1"}, tmpDir)
+	r.Error(err)
+	a.Contains(err.Error(), "exit 1")
+}
+
+func Test_GetMigrationsUnversionedProject(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a template with several migrations
+	tm := newMigratingTemplate(r)
+
+	// When we get the migrations for a project generated from an unversioned template
+	migrations, err := tm.GetMigrations("")
+
+	// Then every migration should be applied
+	r.NoError(err)
+	r.Len(migrations, 3)
+	a.Equal("1.5.0", migrations[0].Version.String())
+}
@@ -0,0 +1,185 @@
+package templateManager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/TheFriendlyCoder/rejigger/lib"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// rejectFileExtension extension added to the name of a file to produce the name of the file
+// containing template changes which could not be merged into it
+const rejectFileExtension = ".rej"
+
+// UpdateProject applies the changes made between two revisions of a template to a project
+// generated from the older revision. baseFS and newFS contain the output of the older and
+// newer revisions of the template, generated in rootDir. The changes are merged into the
+// project found in rootDir of targetFS, preserving changes made to the project since it
+// was generated. Changes which can not be merged are marked with conflict markers in the
+// affected files or, if reject is true, saved to .rej files alongside them. The paths of the
+// files, relative to rootDir, whose changes could not be merged are returned
+func UpdateProject(out io.Writer, baseFS afero.Fs, newFS afero.Fs, targetFS afero.Fs, rootDir string, reject bool) ([]string, error) {
+	baseFiles, err := listFiles(baseFS, rootDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := listFiles(newFS, rootDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	allFiles := map[string]bool{}
+	for curFile := range baseFiles {
+		allFiles[curFile] = true
+	}
+	for curFile := range newFiles {
+		allFiles[curFile] = true
+	}
+	sortedFiles := make([]string, 0, len(allFiles))
+	for curFile := range allFiles {
+		sortedFiles = append(sortedFiles, curFile)
+	}
+	sort.Strings(sortedFiles)
+
+	var conflicts []string
+	for _, relPath := range sortedFiles {
+		conflict, err := updateFile(out, baseFS, newFS, targetFS, rootDir, relPath, reject)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			conflicts = append(conflicts, relPath)
+		}
+	}
+	return conflicts, nil
+}
+
+// updateFile merges the template changes made to a single file of a project, returning
+// true if the changes could not be merged
+func updateFile(out io.Writer, baseFS afero.Fs, newFS afero.Fs, targetFS afero.Fs, rootDir string, relPath string, reject bool) (bool, error) {
+	path := filepath.Join(rootDir, filepath.FromSlash(relPath))
+	base, inBase, err := readOptionalFile(baseFS, path)
+	if err != nil {
+		return false, err
+	}
+	theirs, inNew, err := readOptionalFile(newFS, path)
+	if err != nil {
+		return false, err
+	}
+	ours, inProject, err := readOptionalFile(targetFS, path)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case inBase && inNew && bytes.Equal(base, theirs):
+		// Template didn't change the file, so we keep whatever the project has
+		return false, nil
+	case !inNew:
+		if !inProject {
+			return false, nil
+		}
+		if !bytes.Equal(ours, base) {
+			lib.SNF(fmt.Fprintf(out, "Keeping modified file %s which was removed from the template\n", relPath))
+			return false, nil
+		}
+		lib.SNF(fmt.Fprintf(out, "Removing %s\n", relPath))
+		return false, errors.WithStack(targetFS.Remove(path))
+	case !inProject:
+		if inBase {
+			lib.SNF(fmt.Fprintf(out, "Skipping %s which was removed from the project\n", relPath))
+			return false, nil
+		}
+		lib.SNF(fmt.Fprintf(out, "Adding %s\n", relPath))
+		return false, writeUpdatedFile(newFS, targetFS, path, theirs)
+	case bytes.Equal(ours, theirs):
+		return false, nil
+	case inBase && bytes.Equal(ours, base):
+		lib.SNF(fmt.Fprintf(out, "Updating %s\n", relPath))
+		return false, writeUpdatedFile(newFS, targetFS, path, theirs)
+	}
+
+	// Both the project and the template have changed the file, so we need to merge them
+	if isBinaryContent(base, false) || isBinaryContent(ours, false) || isBinaryContent(theirs, false) {
+		lib.SNF(fmt.Fprintf(out, "Unable to merge changes to binary file %s\n", relPath))
+		return true, nil
+	}
+	merged, clean := mergeText(string(base), string(ours), string(theirs))
+	if clean {
+		lib.SNF(fmt.Fprintf(out, "Merging %s\n", relPath))
+		return false, writeUpdatedFile(newFS, targetFS, path, []byte(merged))
+	}
+	if reject {
+		lib.SNF(fmt.Fprintf(out, "Unable to merge changes to %s, saving them to %s%s\n", relPath, relPath, rejectFileExtension))
+		diff := unifiedDiff(relPath+" (original)", relPath+" (updated)", base, theirs)
+		return true, writeUpdatedFile(newFS, targetFS, path+rejectFileExtension, []byte(diff))
+	}
+	lib.SNF(fmt.Fprintf(out, "Conflicts merging changes to %s\n", relPath))
+	return true, writeUpdatedFile(newFS, targetFS, path, []byte(merged))
+}
+
+// listFiles gets the paths, relative to rootDir, of all files found beneath rootDir. When
+// include is provided, files and folders it rejects are skipped without being walked
+func listFiles(srcFS afero.Fs, rootDir string, include func(relPath string) bool) (map[string]bool, error) {
+	retval := map[string]bool{}
+	err := afero.Walk(srcFS, rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath != "." && include != nil && !include(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			retval[relPath] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list files in "+rootDir)
+	}
+	return retval, nil
+}
+
+// readOptionalFile reads the contents of a file if it exists, returning false if it
+// does not
+func readOptionalFile(srcFS afero.Fs, path string) ([]byte, bool, error) {
+	exists, err := afero.Exists(srcFS, path)
+	if err != nil || !exists {
+		return nil, false, errors.WithStack(err)
+	}
+	data, err := afero.ReadFile(srcFS, path)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return data, true, nil
+}
+
+// writeUpdatedFile writes new content to a project file, creating it with the same mode
+// as the equivalent file generated by the template if it doesn't already exist
+func writeUpdatedFile(newFS afero.Fs, targetFS afero.Fs, path string, data []byte) error {
+	var mode os.FileMode = 0644
+	if info, err := newFS.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+	if err := targetFS.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := afero.WriteFile(targetFS, path, data, mode); err != nil {
+		return errors.Wrap(err, "Failed to update project file "+path)
+	}
+	return nil
+}
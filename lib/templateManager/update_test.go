@@ -0,0 +1,79 @@
+package templateManager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFileSystem creates an in-memory file system containing the given files
+func newFileSystem(r *require.Assertions, files map[string]string) afero.Fs {
+	retval := afero.NewMemMapFs()
+	r.NoError(retval.MkdirAll("/proj", 0755))
+	for name, contents := range files {
+		r.NoError(afero.WriteFile(retval, "/proj/"+name, []byte(contents), 0644))
+	}
+	return retval
+}
+
+func Test_UpdateProject(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given 2 revisions of a generated template
+	baseFS := newFileSystem(r, map[string]string{
+		"unchanged.txt":        "unchanged",
+		"updated.txt":          "original",
+		"removed.txt":          "removed",
+		"removed_modified.txt": "original",
+		"deleted.txt":          "original",
+		"image.bin":            "\x00original",
+	})
+	newFS := newFileSystem(r, map[string]string{
+		"unchanged.txt": "unchanged",
+		"updated.txt":   "updated",
+		"deleted.txt":   "updated",
+		"added.txt":     "added",
+		"image.bin":     "\x00updated",
+	})
+	// and a project generated from the first revision, with some local changes
+	targetFS := newFileSystem(r, map[string]string{
+		"unchanged.txt":        "modified",
+		"updated.txt":          "original",
+		"removed.txt":          "removed",
+		"removed_modified.txt": "modified",
+		"image.bin":            "\x00modified",
+	})
+
+	// When we update the project
+	output := new(bytes.Buffer)
+	conflicts, err := UpdateProject(output, baseFS, newFS, targetFS, "/proj", false)
+	r.NoError(err)
+
+	// Then changes should be applied to files the project hasn't modified
+	expected := map[string]string{
+		"unchanged.txt":        "modified",
+		"updated.txt":          "updated",
+		"removed_modified.txt": "modified",
+		"added.txt":            "added",
+		"image.bin":            "\x00modified",
+	}
+	for name, contents := range expected {
+		actual, err := afero.ReadFile(targetFS, "/proj/"+name)
+		r.NoError(err)
+		a.Equal(contents, string(actual), name)
+	}
+	for _, name := range []string{"removed.txt", "deleted.txt"} {
+		exists, err := afero.Exists(targetFS, "/proj/"+name)
+		r.NoError(err)
+		a.False(exists, name)
+	}
+
+	// and binary files modified by both should be reported as conflicts
+	a.Equal([]string{"image.bin"}, conflicts)
+	a.Contains(output.String(), "Keeping modified file removed_modified.txt")
+	a.Contains(output.String(), "Skipping deleted.txt")
+}
@@ -0,0 +1,72 @@
+package templateManager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseValuesFile parses a YAML file containing pre-defined values for template args
+// and returns them as a mapping of arg names to raw, unparsed values. The file is
+// expected to contain a simple mapping of arg names to values, as in:
+//
+//	project_name: MyProj
+//	use_docker: true
+//	authors: [John, Jane]
+func ParseValuesFile(srcFS afero.Fs, path string) (map[string]string, error) {
+	buf, err := afero.ReadFile(srcFS, path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var rawValues map[string]yaml.Node
+	err = yaml.Unmarshal(buf, &rawValues)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing values file: "+path)
+	}
+
+	// Template args are always parsed from character strings, so we use the text of
+	// each value exactly as it appears in the file. Decoding the values first would
+	// change their text, turning values like 1.0 into 1
+	retval := make(map[string]string, len(rawValues))
+	for name, node := range rawValues {
+		value, err := valueText(&node)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Error parsing value for %s in values file: %s", name, path))
+		}
+		retval[name] = value
+	}
+	return retval, nil
+}
+
+// valueText gets the text of a value loaded from a values file. Lists of values are
+// joined with commas, and null values are treated as empty strings
+func valueText(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, curItem := range node.Content {
+			if curItem.Kind == yaml.AliasNode {
+				curItem = curItem.Alias
+			}
+			if curItem.Kind != yaml.ScalarNode {
+				return "", errors.Errorf("line %d: list items must be simple values", curItem.Line)
+			}
+			items = append(items, curItem.Value)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", errors.Errorf("line %d: expected a simple value or a list of values", node.Line)
+	}
+}
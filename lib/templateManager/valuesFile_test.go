@@ -0,0 +1,90 @@
+package templateManager
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseValuesFile(t *testing.T) {
+	r := require.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a values file containing values of several different types
+	valuesFile := path.Join(tmpDir, "answers.yml")
+	valuesText := `
+project_name: MyProj
+use_docker: true
+port: 8080
+version: 1.0
+build: 007
+authors: [John, Jane]
+empty:
+`
+	r.NoError(os.WriteFile(valuesFile, []byte(valuesText), 0600))
+
+	// When we parse the file
+	result, err := ParseValuesFile(afero.NewOsFs(), valuesFile)
+
+	// Then all values should be loaded exactly as they appear in the file
+	r.NoError(err)
+	r.Equal(map[string]string{
+		"project_name": "MyProj",
+		"use_docker":   "true",
+		"port":         "8080",
+		"version":      "1.0",
+		"build":        "007",
+		"authors":      "John,Jane",
+		"empty":        "",
+	}, result)
+}
+
+func Test_ParseValuesFileInvalidYAML(t *testing.T) {
+	r := require.New(t)
+
+	// Given an empty temp folder
+	tmpDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// and a values file that doesn't contain a mapping
+	valuesFile := path.Join(tmpDir, "answers.yml")
+	r.NoError(os.WriteFile(valuesFile, []byte("not a mapping"), 0600))
+
+	// When we parse the file
+	_, err = ParseValuesFile(afero.NewOsFs(), valuesFile)
+
+	// Then we expect an error
+	r.Error(err)
+	r.Contains(err.Error(), "Error parsing values file")
+}
+
+func Test_ParseValuesFileNestedValues(t *testing.T) {
+	r := require.New(t)
+
+	// Given a values file containing a mapping as the value of an arg
+	srcFS := afero.NewMemMapFs()
+	r.NoError(afero.WriteFile(srcFS, "answers.yml", []byte("project_name: MyProj\nauthor:\n  name: John\n"), 0600))
+
+	// When we parse the file
+	_, err := ParseValuesFile(srcFS, "answers.yml")
+
+	// Then we expect an error identifying the invalid value
+	r.Error(err)
+	r.Contains(err.Error(), "author")
+	r.Contains(err.Error(), "expected a simple value")
+}
+
+func Test_ParseValuesFileNotExist(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ParseValuesFile(afero.NewMemMapFs(), "answers.yml")
+	r.Error(err)
+}
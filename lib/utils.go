@@ -9,6 +9,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	ssh2 "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
@@ -33,35 +36,125 @@ func SNF(args ...any) {
 
 // GetGitFilesystem loads a remote Git repository into an in-memory virtual file system
 func GetGitFilesystem(gitURL string) (afero.Fs, error) {
+	appFS, _, err := CloneGitRepository(gitURL, "")
+	return appFS, err
+}
+
+// getGitAuth gets the credentials needed to access a remote Git repository. SSH keys are
+// used for repositories accessed over SSH, and no credentials are used otherwise
+func getGitAuth(gitURL string) (transport.AuthMethod, error) {
+	if strings.HasPrefix(gitURL, "http") || strings.HasPrefix(gitURL, "file://") {
+		return nil, nil
+	}
+	// TODO: Figure out some way to unit test this block
+	sshFile := fmt.Sprintf("%s/.ssh/id_rsa", os.Getenv("HOME"))
+	_, err := os.Stat(sshFile)
+	if os.IsNotExist(err) {
+		return nil, errors.Wrap(err, fmt.Sprintf("Can not find SSH key %s. Run ssh-keygen first.", sshFile))
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// TODO: add support for encrypted SSH key
+	authKey, err := ssh2.NewPublicKeysFromFile("git", sshFile, "")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return authKey, nil
+}
+
+// ListGitTags gets the names of all tags defined in a remote Git repository, without
+// cloning the repository. The cached copy of the repository is used instead when a
+// Git cache has been configured
+func ListGitTags(gitURL string) ([]string, error) {
+	if gitCache != nil {
+		return gitCache.cachedTags(gitURL)
+	}
+	auth, err := getGitAuth(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{gitURL},
+	})
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list tags in remote Git repository: "+gitURL)
+	}
+	var retval []string
+	for _, curRef := range refs {
+		if curRef.Name().IsTag() {
+			retval = append(retval, curRef.Name().Short())
+		}
+	}
+	return retval, nil
+}
+
+// CloneGitRepository loads a remote Git repository into an in-memory virtual file system,
+// returning the file system along with the hash of the commit that was checked out. If a
+// revision is provided, such as a branch name, tag name or commit hash, it is checked out
+// after cloning. Otherwise, the default branch of the repository is used. The cached copy
+// of the repository is used instead of cloning when a Git cache has been configured
+func CloneGitRepository(gitURL string, revision string) (afero.Fs, string, error) {
+	if gitCache != nil {
+		return gitCache.checkout(gitURL, revision)
+	}
 	appFS := afero.NewMemMapFs()
 	fs := thirdparty.NewBillyWraper(appFS, ".", false)
 
+	auth, err := getGitAuth(gitURL)
+	if err != nil {
+		return appFS, "", err
+	}
 	opts := git.CloneOptions{
-		URL: gitURL,
+		URL:  gitURL,
+		Auth: auth,
 	}
 
-	if !strings.HasPrefix(gitURL, "http") {
-		// TODO: Figure out some way to unit test this block
-		sshFile := fmt.Sprintf("%s/.ssh/id_rsa", os.Getenv("HOME"))
-		_, err := os.Stat(sshFile)
-		if os.IsNotExist(err) {
-			return appFS, errors.Wrap(err, fmt.Sprintf("Can not find SSH key %s. Run ssh-keygen first.", sshFile))
-		} else if err != nil {
-			return appFS, errors.WithStack(err)
-		}
+	repo, err := git.Clone(memory.NewStorage(), fs, &opts)
+	if err != nil {
+		return appFS, "", errors.Wrap(err, "Failed to load remote Git repository: "+gitURL)
+	}
+	hash, err := resolveRevision(repo, gitURL, revision)
+	if err != nil {
+		return appFS, "", err
+	}
+	if revision == "" {
+		return appFS, hash.String(), nil
+	}
 
-		// TODO: add support for encrypted SSH key
-		authKey, err := ssh2.NewPublicKeysFromFile("git", sshFile, "")
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return appFS, "", errors.WithStack(err)
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true})
+	if err != nil {
+		return appFS, "", errors.Wrap(err, fmt.Sprintf("Failed to check out revision %s of Git repository: %s", revision, gitURL))
+	}
+	return appFS, hash.String(), nil
+}
+
+// resolveRevision gets the hash of the commit a revision of a Git repository refers to,
+// such as a branch name, tag name or commit hash. The commit at the HEAD of the repository
+// is used when no revision is provided
+func resolveRevision(repo *git.Repository, gitURL string, revision string) (plumbing.Hash, error) {
+	if revision == "" {
+		head, err := repo.Head()
 		if err != nil {
-			return appFS, errors.WithStack(err)
+			return plumbing.ZeroHash, errors.Wrap(err, "Failed to resolve HEAD of Git repository: "+gitURL)
 		}
-
-		opts.Auth = authKey
+		return head.Hash(), nil
 	}
 
-	_, err := git.Clone(memory.NewStorage(), fs, &opts)
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Only the default branch is checked out locally when cloning, so other branches
+		// can only be found by the name of their remote tracking branch
+		hash, err = repo.ResolveRevision(plumbing.Revision(git.DefaultRemoteName + "/" + revision))
+	}
 	if err != nil {
-		return appFS, errors.Wrap(err, "Failed to load remote Git repository: "+gitURL)
+		return plumbing.ZeroHash, errors.Wrap(err, fmt.Sprintf("Failed to resolve revision %s of Git repository: %s", revision, gitURL))
 	}
-	return appFS, nil
+	return *hash, nil
 }
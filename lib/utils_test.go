@@ -2,11 +2,15 @@ package lib
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
+	"github.com/TheFriendlyCoder/rejigger/lib/internal"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 )
 
 var someerr = fmt.Errorf("Some Failure")
@@ -35,3 +39,136 @@ func Test_getGitFilesystem(t *testing.T) {
 	r.NoError(err)
 	r.True(len(res) > 0)
 }
+
+func Test_CloneGitRepository(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a local Git repository with 2 commits
+	repoDir, err := os.MkdirTemp("", "")
+	r.NoError(err)
+	defer os.RemoveAll(repoDir)
+	_, _, commit := internal.NewGitRepo(r, repoDir, "file.txt")
+	var hashes []string
+	for _, contents := range []string{"Hello", "World"} {
+		hashes = append(hashes, commit(contents).String())
+	}
+
+	tests := map[string]struct {
+		revision string
+		expHash  string
+		expData  string
+	}{
+		"Latest revision": {
+			revision: "",
+			expHash:  hashes[1],
+			expData:  "World",
+		},
+		"Previous revision": {
+			revision: hashes[0],
+			expHash:  hashes[0],
+			expData:  "Hello",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// When we clone the repository
+			fs, hash, err := CloneGitRepository("file://"+repoDir, data.revision)
+			r.NoError(err)
+
+			// Then the selected revision should be checked out
+			a.Equal(data.expHash, hash)
+			contents, err := afero.ReadFile(fs, "file.txt")
+			r.NoError(err)
+			a.Equal(data.expData, string(contents))
+		})
+	}
+
+	// And an error should be returned for revisions that don't exist
+	_, _, err = CloneGitRepository("file://"+repoDir, "fubar")
+	r.Error(err)
+	a.Contains(err.Error(), "fubar")
+}
+
+func Test_CloneGitRepositoryRefs(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a local Git repository with a tagged commit on the default branch
+	repoDir := t.TempDir()
+	repo, worktree, commit := internal.NewGitRepo(r, repoDir, "file.txt")
+	released := commit("Release")
+	_, err := repo.CreateTag("v1.0", released, nil)
+	r.NoError(err)
+	_, err = repo.CreateTag("v1.0-annotated", released, &git.CreateTagOptions{Tagger: internal.Signature(), Message: "Release"})
+	r.NoError(err)
+	latest := commit("Latest")
+
+	// and a feature branch with its own commit
+	r.NoError(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	feature := commit("Feature")
+	r.NoError(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+
+	tests := map[string]struct {
+		ref     string
+		expHash plumbing.Hash
+		expData string
+	}{
+		"Default branch": {
+			ref:     "master",
+			expHash: latest,
+			expData: "Latest",
+		},
+		"Other branch": {
+			ref:     "feature",
+			expHash: feature,
+			expData: "Feature",
+		},
+		"Lightweight tag": {
+			ref:     "v1.0",
+			expHash: released,
+			expData: "Release",
+		},
+		"Annotated tag": {
+			ref:     "v1.0-annotated",
+			expHash: released,
+			expData: "Release",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// When we clone the repository at a specific ref
+			fs, hash, err := CloneGitRepository("file://"+repoDir, data.ref)
+			r.NoError(err)
+
+			// Then the commit the ref points to should be checked out
+			a.Equal(data.expHash.String(), hash)
+			contents, err := afero.ReadFile(fs, "file.txt")
+			r.NoError(err)
+			a.Equal(data.expData, string(contents))
+		})
+	}
+}
+
+func Test_ListGitTags(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a local Git repository with lightweight and annotated tags
+	repoDir := t.TempDir()
+	repo, _, commit := internal.NewGitRepo(r, repoDir, "file.txt")
+	hash := commit("Hello")
+	_, err := repo.CreateTag("v1.0.0", hash, nil)
+	r.NoError(err)
+	_, err = repo.CreateTag("v1.1.0", hash, &git.CreateTagOptions{Tagger: internal.Signature(), Message: "Release"})
+	r.NoError(err)
+
+	// When we list the tags in the repository
+	tags, err := ListGitTags("file://" + repoDir)
+
+	// Then every tag should be found
+	r.NoError(err)
+	a.ElementsMatch([]string{"v1.0.0", "v1.1.0"}, tags)
+}
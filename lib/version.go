@@ -0,0 +1,9 @@
+package lib
+
+// DevVersion version number reported by development builds of the application, which
+// are assumed to support all templates
+const DevVersion = "0.0.0-dev"
+
+// Version version number of the Rejigger application. Release builds set this value
+// at link time using the -X linker flag
+var Version = DevVersion
@@ -0,0 +1,97 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+)
+
+// ParseVersionConstraint parses a constraint describing a range of acceptable versions,
+// like ">= 1.2, < 2.0". In addition to the operators supported by go-version, the caret
+// (^1.2) and tilde (~1.2) operators used by npm and cargo are supported. Caret constraints
+// accept any version which doesn't change the left-most non-zero number and tilde
+// constraints accept any version which only changes the patch number
+func ParseVersionConstraint(value string) (version.Constraints, error) {
+	parts := strings.Split(value, ",")
+	for i, curPart := range parts {
+		curPart = strings.TrimSpace(curPart)
+		var err error
+		switch {
+		case strings.HasPrefix(curPart, "^"):
+			parts[i], err = expandRange(curPart[1:], true)
+		case strings.HasPrefix(curPart, "~") && !strings.HasPrefix(curPart, "~>"):
+			parts[i], err = expandRange(curPart[1:], false)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid version constraint "+value)
+		}
+	}
+	retval, err := version.NewConstraint(strings.Join(parts, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid version constraint "+value)
+	}
+	return retval, nil
+}
+
+// expandRange converts the version from a caret or tilde constraint into the equivalent
+// range of versions, using operators supported by go-version
+func expandRange(value string, caret bool) (string, error) {
+	value = strings.TrimSpace(value)
+	minVersion, err := version.NewVersion(value)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	// Only the numbers given in the constraint are considered, so ^1 is the same as ^1.0.0
+	// but ^0.0 accepts any 0.0.x version
+	numbers := minVersion.Segments()[:len(strings.Split(strings.SplitN(value, "-", 2)[0], "."))]
+
+	// Tilde constraints allow changes to the last number given, or to the patch number
+	// when all 3 numbers are given
+	bump := len(numbers) - 1
+	if bump > 1 {
+		bump = 1
+	}
+	if caret {
+		// Caret constraints allow changes to everything after the left-most non-zero number
+		bump = len(numbers) - 1
+		for i, curNumber := range numbers {
+			if curNumber != 0 {
+				bump = i
+				break
+			}
+		}
+	}
+
+	maxNumbers := make([]string, 0, len(numbers))
+	for i := 0; i <= bump; i++ {
+		curNumber := numbers[i]
+		if i == bump {
+			curNumber++
+		}
+		maxNumbers = append(maxNumbers, fmt.Sprint(curNumber))
+	}
+	return fmt.Sprintf(">= %s, < %s", value, strings.Join(maxNumbers, ".")), nil
+}
+
+// SelectVersionTag gets the name of the tag, from a list of tags, identifying the highest
+// version which satisfies a version constraint. Tags may have a "v" prefix and omit
+// trailing numbers, so "v1.2.3" and "1.2" are both treated as version numbers, but tags
+// which aren't version numbers at all, like "latest", are ignored. Returns false if no
+// tags satisfy the constraint
+func SelectVersionTag(tags []string, constraint version.Constraints) (string, bool) {
+	var retval string
+	var best *version.Version
+	for _, curTag := range tags {
+		curVersion, err := version.NewVersion(curTag)
+		if err != nil || !constraint.Check(curVersion) {
+			continue
+		}
+		if best == nil || curVersion.GreaterThan(best) {
+			best = curVersion
+			retval = curTag
+		}
+	}
+	return retval, best != nil
+}
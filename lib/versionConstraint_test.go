@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseVersionConstraint(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		constraint string
+		accepted   []string
+		rejected   []string
+	}{
+		"Caret major version": {
+			constraint: "^2.1",
+			accepted:   []string{"2.1.0", "2.1.5", "2.9.0"},
+			rejected:   []string{"2.0.9", "3.0.0", "1.9.0"},
+		},
+		"Caret minor version": {
+			constraint: "^0.3.1",
+			accepted:   []string{"0.3.1", "0.3.9"},
+			rejected:   []string{"0.3.0", "0.4.0", "1.0.0"},
+		},
+		"Caret patch version": {
+			constraint: "^0.0.3",
+			accepted:   []string{"0.0.3"},
+			rejected:   []string{"0.0.4", "0.1.0"},
+		},
+		"Caret single number": {
+			constraint: "^1",
+			accepted:   []string{"1.0.0", "1.9.9"},
+			rejected:   []string{"0.9.0", "2.0.0"},
+		},
+		"Tilde patch version": {
+			constraint: "~1.2.3",
+			accepted:   []string{"1.2.3", "1.2.9"},
+			rejected:   []string{"1.2.2", "1.3.0"},
+		},
+		"Tilde minor version": {
+			constraint: "~1.2",
+			accepted:   []string{"1.2.0", "1.2.9"},
+			rejected:   []string{"1.3.0", "1.1.9"},
+		},
+		"Pessimistic operator": {
+			constraint: "~> 1.2",
+			accepted:   []string{"1.2.0", "1.9.0"},
+			rejected:   []string{"2.0.0", "1.1.0"},
+		},
+		"Combined constraints": {
+			constraint: "^1.2, != 1.4.0",
+			accepted:   []string{"1.2.0", "1.5.0"},
+			rejected:   []string{"1.4.0", "2.0.0"},
+		},
+		"Comparison operators": {
+			constraint: ">= 1.0, < 1.5",
+			accepted:   []string{"1.0.0", "1.4.9"},
+			rejected:   []string{"1.5.0", "0.9.0"},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// When we parse the constraint
+			constraint, err := ParseVersionConstraint(data.constraint)
+			r.NoError(err)
+
+			// Then only versions in the expected range should be accepted
+			for _, curVersion := range data.accepted {
+				a.True(constraint.Check(version.Must(version.NewVersion(curVersion))), curVersion)
+			}
+			for _, curVersion := range data.rejected {
+				a.False(constraint.Check(version.Must(version.NewVersion(curVersion))), curVersion)
+			}
+		})
+	}
+}
+
+func Test_ParseVersionConstraintInvalid(t *testing.T) {
+	a := assert.New(t)
+
+	for _, curConstraint := range []string{"^fubar", "~", ">> 1.0", ""} {
+		_, err := ParseVersionConstraint(curConstraint)
+		a.Error(err, curConstraint)
+	}
+}
+
+func Test_SelectVersionTag(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Given a list of tags including some which aren't version numbers
+	tags := []string{"v2.0.0", "v2.1.0", "release", "v2.3.1", "v2.10.0-beta", "v3.0.0", "2.4"}
+
+	// When we select the highest version matching a constraint
+	constraint, err := ParseVersionConstraint("^2.1")
+	r.NoError(err)
+	tag, found := SelectVersionTag(tags, constraint)
+
+	// Then the highest matching release should be selected
+	a.True(found)
+	a.Equal("2.4", tag)
+
+	// And no tag should be selected when nothing matches
+	constraint, err = ParseVersionConstraint("^4")
+	r.NoError(err)
+	_, found = SelectVersionTag(tags, constraint)
+	a.False(found)
+}
+
+func Test_SelectVersionTagFormats(t *testing.T) {
+	tests := map[string]struct {
+		tags       []string
+		constraint string
+		expected   string
+	}{
+		"Prefixed version": {
+			tags:       []string{"v1.2.3", "1.2.2"},
+			constraint: "^1.2",
+			expected:   "v1.2.3",
+		},
+		"Short version": {
+			tags:       []string{"1.2", "1.1.9"},
+			constraint: ">= 1.1",
+			expected:   "1.2",
+		},
+		"Non-version tags": {
+			tags:       []string{"latest", "stable", "1.0.0"},
+			constraint: ">= 0.1",
+			expected:   "1.0.0",
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			a := assert.New(t)
+
+			// Given a list of tags using different version formats
+			constraint, err := ParseVersionConstraint(data.constraint)
+			r.NoError(err)
+
+			// When we select the highest version matching a constraint
+			tag, found := SelectVersionTag(data.tags, constraint)
+
+			// Then the expected tag should be selected
+			a.True(found)
+			a.Equal(data.expected, tag)
+		})
+	}
+}
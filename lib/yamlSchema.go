@@ -0,0 +1,182 @@
+package lib
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// requiredTag name of the struct tag used to mark fields which must be defined in
+// YAML content, as in `required:"true"`
+const requiredTag = "required"
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+)
+
+// CheckYAMLStructure compares the content of a parsed YAML document with the structure of
+// the type it is to be decoded into. A description of every unknown key, value with an
+// incompatible type, and missing required field is returned, each prefixed with the line
+// and column where the problem was found, in the form "source:line:column". Struct fields
+// are matched using their yaml tags and fields tagged with `required:"true"` must be defined
+func CheckYAMLStructure(source string, node *yaml.Node, target reflect.Type) []string {
+	// Empty documents have no content to check
+	if node.Kind == 0 {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	retval := checkNode(node, target, "")
+	for i := range retval {
+		retval[i] = source + ":" + retval[i]
+	}
+	return retval
+}
+
+// checkNode checks a single YAML node, and all of its children, against the given type
+func checkNode(node *yaml.Node, target reflect.Type, path string) []string {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	for target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	switch {
+	case isLeafType(target):
+		return checkScalar(node, target, path)
+	case target.Kind() == reflect.Struct:
+		return checkStruct(node, target, path)
+	case target.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return []string{describeProblem(node, "expected a list for "+displayPath(path))}
+		}
+		var retval []string
+		for i, curNode := range node.Content {
+			retval = append(retval, checkNode(curNode, target.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return retval
+	case target.Kind() == reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return []string{describeProblem(node, "expected a mapping for "+displayPath(path))}
+		}
+		var retval []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			retval = append(retval, checkNode(node.Content[i+1], target.Elem(), joinPath(path, node.Content[i].Value))...)
+		}
+		return retval
+	case target.Kind() == reflect.Interface:
+		return nil
+	default:
+		return checkScalar(node, target, path)
+	}
+}
+
+// checkStruct checks the keys of a YAML mapping against the fields of a struct
+func checkStruct(node *yaml.Node, target reflect.Type, path string) []string {
+	if node.Kind != yaml.MappingNode {
+		return []string{describeProblem(node, "expected a mapping for "+displayPath(path))}
+	}
+
+	var retval []string
+	found := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		field, ok := findField(target, keyNode.Value)
+		if !ok {
+			retval = append(retval, describeProblem(keyNode, "unknown key "+joinPath(path, keyNode.Value)))
+			continue
+		}
+		found[keyNode.Value] = true
+		retval = append(retval, checkNode(node.Content[i+1], field.Type, joinPath(path, keyNode.Value))...)
+	}
+
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		name, ok := fieldName(field)
+		if ok && field.Tag.Get(requiredTag) == "true" && !found[name] {
+			retval = append(retval, describeProblem(node, "missing required key "+joinPath(path, name)))
+		}
+	}
+	return retval
+}
+
+// checkScalar makes sure a YAML node contains a single value compatible with the given type
+func checkScalar(node *yaml.Node, target reflect.Type, path string) []string {
+	if node.Kind != yaml.ScalarNode {
+		return []string{describeProblem(node, "expected a single value for "+displayPath(path))}
+	}
+	if err := node.Decode(reflect.New(target).Interface()); err != nil {
+		return []string{describeProblem(node, fmt.Sprintf("invalid value %q for %s", node.Value, displayPath(path)))}
+	}
+	return nil
+}
+
+// isLeafType checks to see whether values of a type are parsed from a single YAML value
+// rather than from a YAML mapping
+func isLeafType(target reflect.Type) bool {
+	ptr := reflect.PointerTo(target)
+	if ptr.Implements(textUnmarshalerType) {
+		return true
+	}
+	return target.Kind() != reflect.Struct && ptr.Implements(yamlUnmarshalerType)
+}
+
+// findField locates the struct field associated with a YAML key
+func findField(target reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		if name, ok := fieldName(field); ok && name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldName gets the YAML key associated with a struct field, using the same rules as the
+// YAML parser. Returns false if the field is not populated from YAML content
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if len(name) == 0 {
+		name = strings.ToLower(field.Name)
+	}
+	return name, true
+}
+
+// describeProblem prefixes a description of a problem with the location of a YAML node
+func describeProblem(node *yaml.Node, message string) string {
+	return fmt.Sprintf("%d:%d: %s", node.Line, node.Column, message)
+}
+
+// joinPath builds the path to a child of a YAML node
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath gets a printable form of a YAML path
+func displayPath(path string) string {
+	if len(path) == 0 {
+		return "document"
+	}
+	return path
+}
@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// sampleChild nested structure used to test YAML structure checks
+type sampleChild struct {
+	Name  string `yaml:"name" required:"true"`
+	Count int    `yaml:"count"`
+}
+
+// sampleParent structure used to test YAML structure checks
+type sampleParent struct {
+	Version  version.Version   `yaml:"version"`
+	Enabled  bool              `yaml:"enabled"`
+	Children []sampleChild     `yaml:"children"`
+	Labels   map[string]string `yaml:"labels"`
+	Default  string
+	Skipped  string `yaml:"-"`
+}
+
+func Test_CheckYAMLStructure(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tests := map[string]struct {
+		content  string
+		expected []string
+	}{
+		"Valid content": {
+			content: `
+version: 1.2.3
+enabled: true
+children:
+  - name: first
+    count: 3
+labels:
+  a: b
+default: value
+`,
+		},
+		"Empty document": {
+			content: "",
+		},
+		"Unknown keys": {
+			content: `
+enabled: true
+skipped: value
+children:
+  - name: first
+    cuont: 3
+`,
+			expected: []string{
+				"test.yml:3:1: unknown key skipped",
+				"test.yml:6:5: unknown key children[0].cuont",
+			},
+		},
+		"Wrong types": {
+			content: `
+version: fubar
+enabled: maybe
+children:
+  name: first
+labels: [a, b]
+default:
+  nested: value
+`,
+			expected: []string{
+				`test.yml:2:10: invalid value "fubar" for version`,
+				`test.yml:3:10: invalid value "maybe" for enabled`,
+				"test.yml:5:3: expected a list for children",
+				"test.yml:6:9: expected a mapping for labels",
+				"test.yml:8:3: expected a single value for default",
+			},
+		},
+		"Missing required keys": {
+			content: `
+children:
+  - count: 1
+  - name: second
+  - count: 2
+`,
+			expected: []string{
+				"test.yml:3:5: missing required key children[0].name",
+				"test.yml:5:5: missing required key children[2].name",
+			},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Given a YAML document
+			var node yaml.Node
+			r.NoError(yaml.Unmarshal([]byte(data.content), &node))
+
+			// When we check it against the type it will be decoded into
+			result := CheckYAMLStructure("test.yml", &node, reflect.TypeOf(sampleParent{}))
+
+			// Then every problem should be reported with its location
+			a.Equal(data.expected, result)
+		})
+	}
+}